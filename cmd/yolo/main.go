@@ -4,9 +4,12 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/baudevs/yolo.baudevs.com/cmd"
+	"github.com/baudevs/yolo.baudevs.com/internal/ai"
 	"github.com/baudevs/yolo.baudevs.com/internal/commands"
+	"github.com/baudevs/yolo.baudevs.com/internal/logging"
 	"github.com/joho/godotenv"
 	"github.com/spf13/cobra"
 )
@@ -30,8 +33,26 @@ Perfect for:
 🎓 Students
 
 No complicated stuff - just run a command and watch the magic happen!`,
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		commands.SetupLogging(verbose, debug)
+
+		// Tag AI usage with the command that triggered it
+		ai.SetCommand(strings.TrimPrefix(cmd.CommandPath(), "yolo "))
+
+		if showPayload {
+			ai.SetPayloadPreview(commands.PreviewPayload)
+		}
+		commands.ApplyLanguageFlag(cmd)
+	},
 }
 
+var (
+	showPayload   bool
+	explainErrors bool
+	verbose       bool
+	debug         bool
+)
+
 func init() {
 	// Load .env file from the project root
 	if home, err := os.UserHomeDir(); err == nil {
@@ -42,6 +63,11 @@ func init() {
 	// Also try loading from current directory
 	_ = godotenv.Load()
 
+	rootCmd.PersistentFlags().BoolVar(&showPayload, "show-payload", false, "Preview every AI request, after redaction, before it is sent")
+	rootCmd.PersistentFlags().BoolVar(&explainErrors, "explain-errors", false, "Explain failed git commands with AI")
+	rootCmd.PersistentFlags().BoolVar(&verbose, "verbose", false, "Log what yolo does to stderr")
+	rootCmd.PersistentFlags().BoolVar(&debug, "debug", false, "Log everything, including git commands and AI requests, to stderr")
+
 	// Core commands
 	rootCmd.AddCommand(commands.InitCmd())
 	rootCmd.AddCommand(commands.ExplainCmd())
@@ -51,7 +77,13 @@ func init() {
 	// AI commands
 	rootCmd.AddCommand(commands.NewAICommand())
 	rootCmd.AddCommand(commands.NewCommitCommand())
+	rootCmd.AddCommand(commands.NewSquashCommand())
+	rootCmd.AddCommand(commands.NewSyncCommand())
+	rootCmd.AddCommand(commands.NewReviewCommand())
+	rootCmd.AddCommand(commands.NewHooksCommand())
 	rootCmd.AddCommand(commands.NewAskCommand())
+	rootCmd.AddCommand(commands.NewChatCommand())
+	rootCmd.AddCommand(commands.NewExplainErrorCommand())
 
 	// Prompt management
 	rootCmd.AddCommand(commands.NewPromptCommand())
@@ -62,6 +94,9 @@ func init() {
 	rootCmd.AddCommand(commands.EpicCmd())
 	rootCmd.AddCommand(commands.FeatureCmd())
 	rootCmd.AddCommand(commands.TaskCmd())
+	rootCmd.AddCommand(commands.NewShowCommand())
+	rootCmd.AddCommand(commands.NewPlanCommand())
+	rootCmd.AddCommand(commands.NewTranslateCommand())
 	rootCmd.AddCommand(commands.GraphCmd) // Added Graph command
 
 	// License management
@@ -70,7 +105,9 @@ func init() {
 
 func main() {
 	if err := rootCmd.Execute(); err != nil {
-		fmt.Println(err)
+		logging.Debug("command failed", "error", err)
+		fmt.Fprintln(os.Stderr, err)
+		commands.ExplainFailure(err, explainErrors)
 		os.Exit(1)
 	}
 }
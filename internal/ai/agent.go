@@ -0,0 +1,159 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/sashabaranov/go-openai"
+)
+
+// DefaultAgentSteps bounds how many model turns an agent run may take
+const DefaultAgentSteps = 8
+
+// Tool is an operation the model may call during an agent run
+type Tool struct {
+	Name        string
+	Description string
+	Parameters  JSONSchemaDefinition
+	Write       bool // Changes the project, so the user must confirm each call
+
+	run func(ctx context.Context, arguments string) (string, error)
+}
+
+// NewTool creates a tool whose arguments decode into T.
+// The parameter schema is derived from T with SchemaFor, and arguments that
+// do not conform are reported back to the model instead of reaching run.
+func NewTool[T any](name, description string, write bool, run func(ctx context.Context, args T) (string, error)) Tool {
+	var zero T
+	schema := SchemaFor(zero)
+
+	return Tool{
+		Name:        name,
+		Description: description,
+		Parameters:  schema,
+		Write:       write,
+		run: func(ctx context.Context, arguments string) (string, error) {
+			if strings.TrimSpace(arguments) == "" {
+				arguments = "{}"
+			}
+
+			var value interface{}
+			if err := json.Unmarshal([]byte(arguments), &value); err != nil {
+				return "", fmt.Errorf("arguments are not valid JSON: %w", err)
+			}
+			if problems := schema.Validate(value); len(problems) > 0 {
+				return "", fmt.Errorf("invalid arguments: %s", strings.Join(problems, "; "))
+			}
+
+			var args T
+			if err := json.Unmarshal([]byte(arguments), &args); err != nil {
+				return "", fmt.Errorf("failed to decode arguments: %w", err)
+			}
+			return run(ctx, args)
+		},
+	}
+}
+
+// Agent answers a question by letting the model call tools in a bounded loop
+type Agent struct {
+	Model    string
+	Tools    []Tool
+	MaxSteps int
+
+	// Confirm is asked before every call to a write tool; nil declines them all
+	Confirm func(tool Tool, arguments string) bool
+	// OnCall is notified of every tool call, e.g. to show progress
+	OnCall func(tool Tool, arguments string)
+
+	client Provider
+}
+
+// NewAgent creates an agent asking the client's model, with the given tools
+func NewAgent(client *Client, tools []Tool) *Agent {
+	return &Agent{
+		Model:    client.Model(),
+		Tools:    tools,
+		MaxSteps: DefaultAgentSteps,
+		client:   client.Provider(),
+	}
+}
+
+// Run sends the question and executes the tool calls the model makes
+// until it answers, or fails after MaxSteps turns
+func (a *Agent) Run(ctx context.Context, system, question string) (string, error) {
+	tools := make([]openai.Tool, len(a.Tools))
+	byName := make(map[string]Tool, len(a.Tools))
+	for i, tool := range a.Tools {
+		tools[i] = openai.Tool{
+			Type: openai.ToolTypeFunction,
+			Function: openai.FunctionDefinition{
+				Name:        tool.Name,
+				Description: tool.Description,
+				Parameters:  tool.Parameters,
+			},
+		}
+		byName[tool.Name] = tool
+	}
+
+	messages := []openai.ChatCompletionMessage{
+		{Role: openai.ChatMessageRoleSystem, Content: system},
+		{Role: openai.ChatMessageRoleUser, Content: question},
+	}
+
+	for step := 0; step < a.MaxSteps; step++ {
+		resp, err := a.client.CreateChatCompletion(ctx, openai.ChatCompletionRequest{
+			Model:      a.Model,
+			Messages:   messages,
+			Tools:      tools,
+			ToolChoice: "auto",
+		})
+		if err != nil {
+			return "", fmt.Errorf("failed to create chat completion: %w", err)
+		}
+		if len(resp.Choices) == 0 {
+			return "", fmt.Errorf("no response from AI")
+		}
+
+		msg := resp.Choices[0].Message
+		messages = append(messages, msg)
+		if len(msg.ToolCalls) == 0 {
+			return msg.Content, nil
+		}
+
+		for _, call := range msg.ToolCalls {
+			messages = append(messages, openai.ChatCompletionMessage{
+				Role:       openai.ChatMessageRoleTool,
+				Content:    a.call(ctx, byName, call),
+				Name:       call.Function.Name,
+				ToolCallID: call.ID,
+			})
+		}
+	}
+
+	return "", fmt.Errorf("no answer after %d steps, try a narrower question or raise --max-steps", a.MaxSteps)
+}
+
+// call runs one tool call and returns its result for the model.
+// Failures are returned as text so the model can recover from them.
+func (a *Agent) call(ctx context.Context, tools map[string]Tool, call openai.ToolCall) string {
+	tool, ok := tools[call.Function.Name]
+	if !ok {
+		return fmt.Sprintf("Error: unknown tool %q", call.Function.Name)
+	}
+
+	if a.OnCall != nil {
+		a.OnCall(tool, call.Function.Arguments)
+	}
+
+	if tool.Write && (a.Confirm == nil || !a.Confirm(tool, call.Function.Arguments)) {
+		return "The user declined this action. Do not retry it, explain what you would have done instead."
+	}
+
+	result, err := tool.run(ctx, call.Function.Arguments)
+	if err != nil {
+		return "Error: " + err.Error()
+	}
+	return result
+}
@@ -0,0 +1,272 @@
+package ai
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/baudevs/yolo.baudevs.com/internal/logging"
+	"github.com/sashabaranov/go-openai"
+)
+
+const (
+	auditFileName = "audit.jsonl"
+
+	// The audit log is rotated when it grows past auditMaxSize, keeping
+	// auditMaxFiles older files
+	auditMaxSize  = 10 << 20
+	auditMaxFiles = 3
+)
+
+// ErrPayloadRejected is returned when the user declines to send a previewed payload
+var ErrPayloadRejected = errors.New("request not sent to the AI")
+
+// AuditRecord is one request as it was sent to the AI
+type AuditRecord struct {
+	Time       time.Time   `json:"time"`
+	Command    string      `json:"command"`
+	Project    string      `json:"project"`
+	Model      string      `json:"model"`
+	Kind       string      `json:"kind"` // "chat" or "embeddings"
+	Payload    string      `json:"payload"`
+	Redactions []Redaction `json:"redactions,omitempty"`
+}
+
+// PayloadPreview shows an outgoing payload and reports whether to send it
+type PayloadPreview func(payload string, redactions []Redaction) bool
+
+var (
+	previewMu sync.Mutex
+	preview   PayloadPreview
+
+	// approveMu serializes approvals, so concurrent requests never interleave
+	// their previews on the terminal or their records in the audit log
+	approveMu sync.Mutex
+)
+
+// SetPayloadPreview makes every request wait for the given preview to approve it
+func SetPayloadPreview(p PayloadPreview) {
+	previewMu.Lock()
+	defer previewMu.Unlock()
+	preview = p
+}
+
+// redactingProvider masks secrets in every request and records it in the audit log
+type redactingProvider struct {
+	next Provider
+}
+
+func (p *redactingProvider) CreateChatCompletion(ctx context.Context, req openai.ChatCompletionRequest) (openai.ChatCompletionResponse, error) {
+	r, err := DefaultRedactor()
+	if err != nil {
+		return openai.ChatCompletionResponse{}, err
+	}
+
+	counts := make(map[string]int)
+	messages := make([]openai.ChatCompletionMessage, len(req.Messages))
+	var payload strings.Builder
+	for i, msg := range req.Messages {
+		var redactions []Redaction
+		msg.Content, redactions = r.Redact(msg.Content)
+		addRedactions(counts, redactions)
+		if msg.FunctionCall != nil {
+			call := *msg.FunctionCall
+			call.Arguments, redactions = r.Redact(call.Arguments)
+			addRedactions(counts, redactions)
+			msg.FunctionCall = &call
+		}
+		if len(msg.ToolCalls) > 0 {
+			calls := make([]openai.ToolCall, len(msg.ToolCalls))
+			for j, call := range msg.ToolCalls {
+				call.Function.Arguments, redactions = r.Redact(call.Function.Arguments)
+				addRedactions(counts, redactions)
+				calls[j] = call
+			}
+			msg.ToolCalls = calls
+		}
+		messages[i] = msg
+
+		fmt.Fprintf(&payload, "[%s]\n%s\n\n", msg.Role, msg.Content)
+		if msg.FunctionCall != nil {
+			fmt.Fprintf(&payload, "%s(%s)\n\n", msg.FunctionCall.Name, msg.FunctionCall.Arguments)
+		}
+		for _, call := range msg.ToolCalls {
+			fmt.Fprintf(&payload, "%s(%s)\n\n", call.Function.Name, call.Function.Arguments)
+		}
+	}
+	req.Messages = messages
+
+	if err := approve(req.Model, "chat", strings.TrimSpace(payload.String()), sortedRedactions(counts)); err != nil {
+		return openai.ChatCompletionResponse{}, err
+	}
+	logging.Debug("AI request", "model", req.Model, "messages", len(messages), "redactions", len(counts),
+		logging.Prompt(strings.TrimSpace(payload.String())))
+
+	return p.next.CreateChatCompletion(ctx, req)
+}
+
+func (p *redactingProvider) CreateEmbeddings(ctx context.Context, conv openai.EmbeddingRequestConverter) (openai.EmbeddingResponse, error) {
+	embedder, ok := p.next.(Embedder)
+	if !ok {
+		return openai.EmbeddingResponse{}, ErrEmbeddingsUnsupported
+	}
+
+	r, err := DefaultRedactor()
+	if err != nil {
+		return openai.EmbeddingResponse{}, err
+	}
+
+	req := conv.Convert()
+	texts, ok := req.Input.([]string)
+	if !ok {
+		return openai.EmbeddingResponse{}, fmt.Errorf("only text embeddings can be redacted")
+	}
+
+	counts := make(map[string]int)
+	redacted := make([]string, len(texts))
+	for i, text := range texts {
+		var redactions []Redaction
+		redacted[i], redactions = r.Redact(text)
+		addRedactions(counts, redactions)
+	}
+
+	if err := approve(req.Model.String(), "embeddings", strings.Join(redacted, "\n\n"), sortedRedactions(counts)); err != nil {
+		return openai.EmbeddingResponse{}, err
+	}
+
+	return embedder.CreateEmbeddings(ctx, openai.EmbeddingRequestStrings{
+		Input: redacted,
+		Model: req.Model,
+		User:  req.User,
+	})
+}
+
+// approve runs the payload preview, if any, and records the payload in the audit log
+func approve(model, kind, payload string, redactions []Redaction) error {
+	approveMu.Lock()
+	defer approveMu.Unlock()
+
+	previewMu.Lock()
+	p := preview
+	previewMu.Unlock()
+
+	if p != nil && !p(payload, redactions) {
+		return ErrPayloadRejected
+	}
+
+	usageMu.Lock()
+	command := usageCommand
+	usageMu.Unlock()
+
+	record := AuditRecord{
+		Time:       time.Now().UTC(),
+		Command:    command,
+		Project:    currentProject(),
+		Model:      model,
+		Kind:       kind,
+		Payload:    payload,
+		Redactions: redactions,
+	}
+	if err := appendAudit(record); err != nil {
+		logging.Warn("failed to write AI audit log", "error", err)
+	}
+	return nil
+}
+
+// LoadAudit reads all audit records created at or after since, from the
+// rotated files too
+func LoadAudit(since time.Time) ([]AuditRecord, error) {
+	path, err := auditPath()
+	if err != nil {
+		return nil, err
+	}
+
+	var records []AuditRecord
+	for _, file := range logging.RotatedFiles(path) {
+		loaded, err := loadAuditFile(file, since)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, loaded...)
+	}
+	return records, nil
+}
+
+// loadAuditFile reads the records of one audit file created at or after since
+func loadAuditFile(path string, since time.Time) ([]AuditRecord, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to open audit log: %w", err)
+	}
+	defer f.Close()
+
+	var records []AuditRecord
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		var record AuditRecord
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			continue // Skip corrupt lines rather than failing the whole report
+		}
+		if !record.Time.Before(since) {
+			records = append(records, record)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read audit log: %w", err)
+	}
+
+	return records, nil
+}
+
+// appendAudit writes a single record as one JSON line
+func appendAudit(record AuditRecord) error {
+	path, err := auditPath()
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to marshal audit record: %w", err)
+	}
+
+	// The payloads are redacted, but still project content: keep them private
+	f, err := logging.OpenRotated(path, auditMaxSize, auditMaxFiles)
+	if err != nil {
+		return fmt.Errorf("failed to open audit log: %w", err)
+	}
+	defer f.Close()
+
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write audit record: %w", err)
+	}
+
+	return nil
+}
+
+// auditPath returns the path to the audit log
+func auditPath() (string, error) {
+	configDir, err := getConfigDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(configDir, auditFileName), nil
+}
+
+func addRedactions(counts map[string]int, redactions []Redaction) {
+	for _, r := range redactions {
+		counts[r.Rule] += r.Count
+	}
+}
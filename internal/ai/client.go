@@ -11,16 +11,20 @@ import (
 
 // Client handles communication with the AI service
 type Client struct {
-	client *openai.Client
+	client Provider
+	model  string
 }
 
+// DefaultModel is the chat model used when none is configured
+const DefaultModel = openai.GPT4TurboPreview
+
 // NewClient creates a new AI client
 func NewClient(cfg *config.Config, licenseManager *license.Manager) (*Client, error) {
 	// Try to get API key from config first
 	apiKey := cfg.OpenAI.APIKey
 
-	// If not in config, try license manager
-	if apiKey == "" {
+	// If not in config, try license manager (the mock provider needs no key)
+	if apiKey == "" && !MockEnabled() {
 		var err error
 		apiKey, err = licenseManager.GetOpenAIKey()
 		if err != nil {
@@ -28,26 +32,58 @@ func NewClient(cfg *config.Config, licenseManager *license.Manager) (*Client, er
 		}
 	}
 
-	// Create OpenAI client
-	client := openai.NewClient(apiKey)
+	model := cfg.AI.Model
+	if model == "" {
+		model = DefaultModel
+	}
 
 	return &Client{
-		client: client,
+		client: NewProvider(apiKey),
+		model:  model,
 	}, nil
 }
 
+// NewClientWithProvider creates an AI client backed by the given provider,
+// e.g. a MockProvider in tests
+func NewClientWithProvider(provider Provider) *Client {
+	return &Client{
+		client: provider,
+		model:  DefaultModel,
+	}
+}
+
+// WithModel returns a client sending the same requests to another model
+func (c *Client) WithModel(model string) *Client {
+	return &Client{client: c.client, model: model}
+}
+
+// Model returns the chat model the client asks
+func (c *Client) Model() string {
+	return c.model
+}
+
+// Provider returns the provider the client sends requests through
+func (c *Client) Provider() Provider {
+	return c.client
+}
+
 // Ask sends a question to the AI and returns the response
 func (c *Client) Ask(ctx context.Context, prompt string) (string, error) {
+	return c.Chat(ctx, []openai.ChatCompletionMessage{
+		{
+			Role:    openai.ChatMessageRoleUser,
+			Content: prompt,
+		},
+	})
+}
+
+// Chat sends a conversation to the AI and returns the next reply
+func (c *Client) Chat(ctx context.Context, messages []openai.ChatCompletionMessage) (string, error) {
 	resp, err := c.client.CreateChatCompletion(
 		ctx,
 		openai.ChatCompletionRequest{
-			Model: openai.GPT4TurboPreview,
-			Messages: []openai.ChatCompletionMessage{
-				{
-					Role:    openai.ChatMessageRoleUser,
-					Content: prompt,
-				},
-			},
+			Model:    c.model,
+			Messages: messages,
 		},
 	)
 
@@ -62,49 +98,60 @@ func (c *Client) Ask(ctx context.Context, prompt string) (string, error) {
 	return resp.Choices[0].Message.Content, nil
 }
 
-// CreateFunctionCall sends a request to the AI with function definitions and returns the function call response
-func (c *Client) CreateFunctionCall(ctx context.Context, messages []openai.ChatCompletionMessage, functions []openai.FunctionDefinition) (string, error) {
+// CreateFunctionCall offers the functions to the AI as tools and returns the
+// call of the one it chose, with its arguments
+func (c *Client) CreateFunctionCall(ctx context.Context, messages []openai.ChatCompletionMessage, functions []openai.FunctionDefinition) (openai.FunctionCall, error) {
+	tools := make([]openai.Tool, len(functions))
+	for i, function := range functions {
+		tools[i] = openai.Tool{Type: openai.ToolTypeFunction, Function: function}
+	}
+
 	resp, err := c.client.CreateChatCompletion(
 		ctx,
 		openai.ChatCompletionRequest{
-			Model:     openai.GPT4TurboPreview,
-			Messages:  messages,
-			Functions: functions,
-			FunctionCall: &openai.FunctionCall{
-				Name: functions[0].Name,
-			},
+			Model:      c.model,
+			Messages:   messages,
+			Tools:      tools,
+			ToolChoice: "auto",
 		},
 	)
 
 	if err != nil {
-		return "", fmt.Errorf("failed to create function call: %w", err)
+		return openai.FunctionCall{}, fmt.Errorf("failed to create function call: %w", err)
 	}
 
 	if len(resp.Choices) == 0 {
-		return "", fmt.Errorf("no response from AI")
+		return openai.FunctionCall{}, fmt.Errorf("no response from AI")
 	}
 
-	if resp.Choices[0].Message.FunctionCall == nil {
-		return "", fmt.Errorf("no function call in response")
+	if len(resp.Choices[0].Message.ToolCalls) == 0 {
+		return openai.FunctionCall{}, fmt.Errorf("no function call in response")
 	}
 
-	return resp.Choices[0].Message.FunctionCall.Arguments, nil
+	return resp.Choices[0].Message.ToolCalls[0].Function, nil
 }
 
 // GenerateCommitMessage generates a commit message based on the diff
 func (c *Client) GenerateCommitMessage(ctx context.Context, diff string) (string, error) {
-	prompt := fmt.Sprintf(`Generate a commit message for this diff:
-
-%s
+	prompt, err := RenderPrompt("commit", PromptData{"Diff": diff})
+	if err != nil {
+		return "", err
+	}
 
-The commit message should:
-- Follow the Conventional Commits specification
-- Be concise but descriptive
-- Focus on the main changes
-- Use present tense
-- Not exceed 100 characters for the first line
+	return c.Ask(ctx, prompt)
+}
 
-Respond with just the commit message, nothing else.`, diff)
+// ReviseCommitMessage regenerates a commit message following the author's hint,
+// e.g. "focus on the parser change"
+func (c *Client) ReviseCommitMessage(ctx context.Context, diff, message, hint string) (string, error) {
+	prompt, err := RenderPrompt("commit.revise", PromptData{
+		"Diff":    diff,
+		"Message": message,
+		"Hint":    hint,
+	})
+	if err != nil {
+		return "", err
+	}
 
 	return c.Ask(ctx, prompt)
 }
@@ -0,0 +1,40 @@
+package ai
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sashabaranov/go-openai"
+)
+
+func TestCreateFunctionCallLetsTheModelChoose(t *testing.T) {
+	mock, err := NewMockProvider(MockFixture{
+		Match:    `start T003`,
+		Response: `{"tool_calls": [{"name": "set_status", "arguments": {"id": "T003", "status": "in-progress"}}]}`,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	functions := []openai.FunctionDefinition{
+		{Name: "list_items", Parameters: SchemaFor(struct{}{})},
+		{Name: "set_status", Parameters: SchemaFor(struct {
+			ID     string `json:"id"`
+			Status string `json:"status"`
+		}{})},
+	}
+	call, err := NewClientWithProvider(mock).CreateFunctionCall(context.Background(), []openai.ChatCompletionMessage{
+		{Role: openai.ChatMessageRoleUser, Content: "start T003"},
+	}, functions)
+	if err != nil {
+		t.Fatalf("CreateFunctionCall: %v", err)
+	}
+	if call.Name != "set_status" || call.Arguments != `{"id": "T003", "status": "in-progress"}` {
+		t.Errorf("call = %+v, want set_status on T003", call)
+	}
+
+	request := mock.Requests()[0]
+	if request.ToolChoice != "auto" || request.FunctionCall != nil {
+		t.Errorf("the request forces a function: tool_choice %v, function_call %v", request.ToolChoice, request.FunctionCall)
+	}
+}
@@ -4,335 +4,307 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"os"
-	"path/filepath"
+	"slices"
 	"strings"
-	"time"
+
+	"github.com/baudevs/yolo.baudevs.com/internal/commitmsg"
+	"github.com/baudevs/yolo.baudevs.com/internal/config"
+	"github.com/baudevs/yolo.baudevs.com/internal/logging"
 	"github.com/baudevs/yolo.baudevs.com/internal/models"
+	"github.com/baudevs/yolo.baudevs.com/internal/utils"
 	"github.com/sashabaranov/go-openai"
-	"os/exec"
 )
 
-// CommitAI handles AI-powered commit message generation
-type CommitAI struct {
-	client *openai.Client
-}
-
-// NewCommitAI creates a new CommitAI instance
-func NewCommitAI(apiKey string) (*CommitAI, error) {
-	if apiKey == "" {
-		return nil, fmt.Errorf("OpenAI API key is required")
-	}
+// Chunking limits of CommitAI
+const (
+	maxChunkSize = 6000 // Conservative size to stay under token limit
+	maxChunks    = 5
+)
 
-	return &CommitAI{
-		client: openai.NewClient(apiKey),
-	}, nil
+// commitDraft is the commit message the AI writes. It has no issue refs or
+// co-authors: those come from the linked tasks and --co-author only, never
+// from the model.
+type commitDraft struct {
+	Type           string `json:"type" enum:"feat,fix,docs,style,refactor,perf,test,build,ci,chore" description:"Conventional commit type"`
+	Scope          string `json:"scope,omitempty" description:"Optional area affected"`
+	Subject        string `json:"subject" description:"Concise description in present tense"`
+	Body           string `json:"body,omitempty" description:"Key changes"`
+	Breaking       bool   `json:"breaking,omitempty" description:"Whether the change breaks compatibility"`
+	BreakingChange string `json:"breaking_change,omitempty" description:"When breaking: what breaks and how to migrate"`
 }
 
-// getDebugDir returns the path to the debug directory
-func (ai *CommitAI) getDebugDir() (string, error) {
-	// Get project root by looking for go.mod
-	cmd := exec.Command("git", "rev-parse", "--show-toplevel")
-	out, err := cmd.Output()
-	if err != nil {
-		return "", fmt.Errorf("failed to find project root: %w", err)
-	}
-
-	projectRoot := strings.TrimSpace(string(out))
-	debugDir := filepath.Join(projectRoot, ".yolo-debug")
-	if err := os.MkdirAll(debugDir, 0755); err != nil {
-		return "", fmt.Errorf("failed to create debug directory: %w", err)
+// message returns the draft as a CommitMessage
+func (d commitDraft) message() models.CommitMessage {
+	return models.CommitMessage{
+		Type:           d.Type,
+		Scope:          d.Scope,
+		Subject:        d.Subject,
+		Body:           d.Body,
+		Breaking:       d.Breaking,
+		BreakingChange: d.BreakingChange,
 	}
+}
 
-	return debugDir, nil
+// CommitAI handles AI-powered commit message generation
+type CommitAI struct {
+	client    *Client
+	formatter commitmsg.Formatter
+	issueRefs []string
 }
 
-// Debug logs information to both console and file
-func (ai *CommitAI) debug(format string, args ...interface{}) {
-	msg := fmt.Sprintf(format, args...)
-	fmt.Println("DEBUG:", msg)
-	
-	debugDir, err := ai.getDebugDir()
-	if err != nil {
-		fmt.Printf("Warning: Could not get debug directory: %v\n", err)
-		return
-	}
+// NewCommitAI creates a new CommitAI instance asking the client's model and
+// writing Conventional Commits
+func NewCommitAI(client *Client) *CommitAI {
+	formatter, _ := commitmsg.NewFormatter(commitmsg.FormatConventional, "")
+	return &CommitAI{client: client, formatter: formatter}
+}
 
-	logFile := filepath.Join(debugDir, "yolo-commit.log")
-	f, err := os.OpenFile(logFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
-	if err != nil {
-		fmt.Printf("Warning: Could not open log file: %v\n", err)
-		return
-	}
-	defer f.Close()
+// WithFormatter sets how messages are written, e.g. as gitmoji
+func (ai *CommitAI) WithFormatter(formatter commitmsg.Formatter) *CommitAI {
+	ai.formatter = formatter
+	return ai
+}
 
-	timestamp := time.Now().Format("2006-01-02 15:04:05")
-	fmt.Fprintf(f, "[%s] %s\n", timestamp, msg)
+// WithIssueRefs sets refs every message references, e.g. the linked tasks
+func (ai *CommitAI) WithIssueRefs(refs ...string) *CommitAI {
+	ai.issueRefs = refs
+	return ai
 }
 
 // analyzeChunk sends a portion of changes to OpenAI and returns the analysis
-func (ai *CommitAI) analyzeChunk(ctx context.Context, changes string, chunkNum, totalChunks int, isSummary bool) (models.CommitMessage, error) {
-	var contextNote string
-	if isSummary {
-		contextNote = "This is a summarized view showing only file names and line counts."
-	} else {
-		contextNote = "This is a full diff showing actual code changes."
+func (ai *CommitAI) analyzeChunk(ctx context.Context, changes string, chunkNum, totalChunks int, isSummary bool) (commitDraft, error) {
+	prompt, err := RenderPrompt("commit.chunk", PromptData{
+		"Part":       chunkNum,
+		"Total":      totalChunks,
+		"Summarized": isSummary,
+		"Changes":    changes,
+	})
+	if err != nil {
+		return commitDraft{}, err
 	}
 
-	prompt := fmt.Sprintf(`Analyze the following Git changes (part %d of %d) and generate a conventional commit message in JSON format.
-Note: Focus on understanding the changes in this chunk, a final summary will be generated later.
-
-%s
-
-Follow these rules:
-1. Use semantic commit types: feat, fix, docs, style, refactor, perf, test, build, ci, chore
-2. Keep the subject line clear and concise
-3. Use present tense ("add" not "added")
-4. Focus on the main changes in this chunk
-5. Include any breaking changes found
-
-The response must be a valid JSON object with this structure:
-{
-  "type": "feat|fix|docs|style|refactor|perf|test|build|ci|chore",
-  "scope": "optional area affected",
-  "subject": "concise description",
-  "body": "key changes in this chunk",
-  "breaking": false,
-  "issue_refs": ["optional array of issue references"],
-  "co_authors": ["optional array of co-authors"]
-}
-
-Changes to analyze:
-%s`, chunkNum, totalChunks, contextNote, changes)
-
-	ai.debug("Analyzing chunk %d of %d (length: %d bytes)", chunkNum, totalChunks, len(changes))
+	logging.Debug("analyzing commit chunk", "part", chunkNum, "total", totalChunks, "bytes", len(changes))
 
-	resp, err := ai.client.CreateChatCompletion(
+	msg, err := GenerateStructured[commitDraft](
 		ctx,
-		openai.ChatCompletionRequest{
-			Model: openai.GPT4,
+		ai.client.Provider(),
+		StructuredRequest{
+			Model: ai.client.Model(),
 			Messages: []openai.ChatCompletionMessage{
 				{
 					Role:    openai.ChatMessageRoleUser,
 					Content: prompt,
 				},
 			},
+			Name:        "commit_message",
+			Description: "Report the conventional commit message for these changes",
 			Temperature: 0.2,
 		},
 	)
-
 	if err != nil {
-		return models.CommitMessage{}, fmt.Errorf("failed to analyze chunk %d: %w", chunkNum, err)
-	}
-
-	if len(resp.Choices) == 0 {
-		return models.CommitMessage{}, fmt.Errorf("no analysis generated for chunk %d", chunkNum)
-	}
-
-	content := strings.TrimSpace(resp.Choices[0].Message.Content)
-	var msg models.CommitMessage
-	if err := json.Unmarshal([]byte(content), &msg); err != nil {
-		return models.CommitMessage{}, fmt.Errorf("failed to parse chunk %d analysis: %w", chunkNum, err)
+		return commitDraft{}, fmt.Errorf("failed to analyze chunk %d: %w", chunkNum, err)
 	}
 
 	return msg, nil
 }
 
 // summarizeAnalyses combines multiple chunk analyses into a final commit message
-func (ai *CommitAI) summarizeAnalyses(ctx context.Context, analyses []models.CommitMessage, hadToTruncate bool) (models.CommitMessage, error) {
+func (ai *CommitAI) summarizeAnalyses(ctx context.Context, analyses []commitDraft, plan chunkPlan) (commitDraft, error) {
 	// Convert analyses to a JSON array for the AI to process
 	analysesJSON, err := json.MarshalIndent(analyses, "", "  ")
 	if err != nil {
-		return models.CommitMessage{}, fmt.Errorf("failed to marshal analyses: %w", err)
+		return commitDraft{}, fmt.Errorf("failed to marshal analyses: %w", err)
 	}
 
-	var truncationNote string
-	if hadToTruncate {
-		truncationNote = "\nNote: Some changes were too large and had to be truncated."
+	prompt, err := RenderPrompt("commit.summary", PromptData{
+		"Truncated": plan.Truncated,
+		"Skipped":   plan.SkippedSummary(),
+		"Analyses":  string(analysesJSON),
+	})
+	if err != nil {
+		return commitDraft{}, err
 	}
 
-	prompt := fmt.Sprintf(`Analyze these commit message summaries and create a single, comprehensive commit message.
-The summaries represent different parts of a large change set.%s
-
-Previous analyses:
-%s
-
-Generate a final commit message that:
-1. Uses the most appropriate commit type based on all changes
-2. Creates a clear, concise subject line that captures the main change
-3. Includes a body that summarizes the key changes
-4. Preserves any breaking changes, issue references, or co-authors
-5. Follows conventional commit format
-
-Respond with a single JSON object using the same structure as the input.`, truncationNote, string(analysesJSON))
-
-	resp, err := ai.client.CreateChatCompletion(
+	finalMsg, err := GenerateStructured[commitDraft](
 		ctx,
-		openai.ChatCompletionRequest{
-			Model: openai.GPT4,
+		ai.client.Provider(),
+		StructuredRequest{
+			Model: ai.client.Model(),
 			Messages: []openai.ChatCompletionMessage{
 				{
 					Role:    openai.ChatMessageRoleUser,
 					Content: prompt,
 				},
 			},
+			Name:        "commit_message",
+			Description: "Report the final conventional commit message",
 			Temperature: 0.2,
 		},
 	)
-
 	if err != nil {
-		return models.CommitMessage{}, fmt.Errorf("failed to generate final summary: %w", err)
-	}
-
-	if len(resp.Choices) == 0 {
-		return models.CommitMessage{}, fmt.Errorf("no final summary generated")
-	}
-
-	content := strings.TrimSpace(resp.Choices[0].Message.Content)
-	var finalMsg models.CommitMessage
-	if err := json.Unmarshal([]byte(content), &finalMsg); err != nil {
-		return models.CommitMessage{}, fmt.Errorf("failed to parse final summary: %w", err)
+		return commitDraft{}, fmt.Errorf("failed to generate final summary: %w", err)
 	}
 
 	return finalMsg, nil
 }
 
-// GenerateCommitMessage generates a commit message based on the changes
+// GenerateCommitMessage generates a commit message based on the changes.
+// A diff is split at file and hunk boundaries and its most significant files
+// are analyzed first; noise such as lockfiles and generated code, and files
+// beyond the size limits, are only described by their line counts. The bool
+// reports whether files were left out for lack of room.
 func (ai *CommitAI) GenerateCommitMessage(ctx context.Context, changes string) (string, bool, error) {
-	ai.debug("Starting commit message generation")
-	ai.debug("Total changes length: %d bytes", len(changes))
-
-	// Save full changes to file for reference
-	debugDir, err := ai.getDebugDir()
+	analyses, plan, err := ai.analyzeChanges(ctx, changes)
 	if err != nil {
-		return "", false, fmt.Errorf("failed to get debug directory: %w", err)
-	}
-
-	timestamp := time.Now().Unix()
-	changesFile := filepath.Join(debugDir, fmt.Sprintf("changes-%d.txt", timestamp))
-	if err := os.WriteFile(changesFile, []byte(changes), 0644); err != nil {
-		ai.debug("Warning: Could not write changes file: %v", err)
+		return "", plan.Truncated, err
 	}
 
-	// Check if this is a summarized diff
-	isSummary := strings.HasPrefix(changes, "Changed files summary:")
-
-	// Split changes into smaller chunks
-	const maxChunkSize = 6000 // Conservative size to stay under token limit
-	var chunks []string
-	lines := strings.Split(changes, "\n")
-	
-	currentChunk := strings.Builder{}
-	currentSize := 0
-	
-	for _, line := range lines {
-		lineSize := len(line) + 1 // +1 for newline
-		if currentSize+lineSize > maxChunkSize && currentSize > 0 {
-			chunks = append(chunks, currentChunk.String())
-			currentChunk.Reset()
-			currentSize = 0
+	// Generate final summary
+	if len(analyses) == 1 {
+		// If only one chunk, use its analysis directly
+		formattedMsg, err := ai.FormatCommitMessage(analyses[0].message())
+		if err != nil {
+			return "", plan.Truncated, err
 		}
-		currentChunk.WriteString(line)
-		currentChunk.WriteString("\n")
-		currentSize += lineSize
+		return formattedMsg, plan.Truncated, nil
 	}
-	
-	if currentChunk.Len() > 0 {
-		chunks = append(chunks, currentChunk.String())
+
+	// Combine analyses into final message
+	finalMsg, err := ai.summarizeAnalyses(ctx, analyses, plan)
+	if err != nil {
+		return "", plan.Truncated, fmt.Errorf("failed to generate final summary: %w", err)
 	}
 
-	ai.debug("Split changes into %d chunks", len(chunks))
-
-	// If we have too many chunks, only process the most important ones
-	const maxChunks = 5
-	hadToTruncate := false
-	
-	if len(chunks) > maxChunks && !isSummary {
-		hadToTruncate = true
-		ai.debug("Too many chunks (%d), truncating to %d", len(chunks), maxChunks)
-		
-		// Keep first two and last two chunks
-		truncatedChunks := make([]string, 0, maxChunks)
-		truncatedChunks = append(truncatedChunks, chunks[0:2]...)
-		
-		// Add a summary chunk in the middle
-		summary := fmt.Sprintf("\n... %d chunks truncated ...\n", len(chunks)-4)
-		truncatedChunks = append(truncatedChunks, summary)
-		
-		truncatedChunks = append(truncatedChunks, chunks[len(chunks)-2:]...)
-		chunks = truncatedChunks
+	formattedMsg, err := ai.FormatCommitMessage(finalMsg.message())
+	if err != nil {
+		return "", plan.Truncated, err
 	}
+	return formattedMsg, plan.Truncated, nil
+}
 
-	// Analyze each chunk
-	var analyses []models.CommitMessage
-	for i, chunk := range chunks {
-		chunkNum := i + 1
-		analysis, err := ai.analyzeChunk(ctx, chunk, chunkNum, len(chunks), isSummary)
-		if err != nil {
-			return "", hadToTruncate, fmt.Errorf("failed to analyze chunk %d: %w", chunkNum, err)
-		}
-		analyses = append(analyses, analysis)
-		ai.debug("Successfully analyzed chunk %d", chunkNum)
+// GenerateSquashMessage writes one message for commits squashed together,
+// from the diff of the whole range and the messages of its commits. The
+// bool reports whether files were left out for lack of room.
+func (ai *CommitAI) GenerateSquashMessage(ctx context.Context, changes string, messages []string) (string, bool, error) {
+	logging.Debug("generating squash message", "commits", len(messages))
+
+	analyses, plan, err := ai.analyzeChanges(ctx, changes)
+	if err != nil {
+		return "", plan.Truncated, err
 	}
 
-	// Generate final summary
-	if len(analyses) == 1 && !hadToTruncate {
-		// If only one chunk and no truncation, use its analysis directly
-		formattedMsg := ai.FormatCommitMessage(analyses[0])
-		ai.debug("Generated commit message from single chunk: %s", formattedMsg)
-		return formattedMsg, false, nil
+	analysesJSON, err := json.MarshalIndent(analyses, "", "  ")
+	if err != nil {
+		return "", plan.Truncated, fmt.Errorf("failed to marshal analyses: %w", err)
+	}
+	prompt, err := RenderPrompt("commit.squash", PromptData{
+		"Truncated": plan.Truncated,
+		"Skipped":   plan.SkippedSummary(),
+		"Analyses":  string(analysesJSON),
+		"Messages":  strings.Join(messages, "\n---\n"),
+	})
+	if err != nil {
+		return "", plan.Truncated, err
 	}
 
-	// Combine analyses into final message
-	finalMsg, err := ai.summarizeAnalyses(ctx, analyses, hadToTruncate)
+	finalMsg, err := GenerateStructured[commitDraft](
+		ctx,
+		ai.client.Provider(),
+		StructuredRequest{
+			Model: ai.client.Model(),
+			Messages: []openai.ChatCompletionMessage{
+				{
+					Role:    openai.ChatMessageRoleUser,
+					Content: prompt,
+				},
+			},
+			Name:        "commit_message",
+			Description: "Report the consolidated conventional commit message",
+			Temperature: 0.2,
+		},
+	)
 	if err != nil {
-		return "", hadToTruncate, fmt.Errorf("failed to generate final summary: %w", err)
+		return "", plan.Truncated, fmt.Errorf("failed to generate squash message: %w", err)
 	}
 
-	formattedMsg := ai.FormatCommitMessage(finalMsg)
-	ai.debug("Generated final commit message: %s", formattedMsg)
-	return formattedMsg, hadToTruncate, nil
+	formattedMsg, err := ai.FormatCommitMessage(finalMsg.message())
+	if err != nil {
+		return "", plan.Truncated, err
+	}
+	return formattedMsg, plan.Truncated, nil
 }
 
-// FormatCommitMessage formats a CommitMessage into a conventional commit string
-func (ai *CommitAI) FormatCommitMessage(msg models.CommitMessage) string {
-	var sb strings.Builder
+// analyzeChanges splits the changes into chunks and analyzes them in parallel
+func (ai *CommitAI) analyzeChanges(ctx context.Context, changes string) ([]commitDraft, chunkPlan, error) {
+	logging.Debug("analyzing commit changes", "bytes", len(changes))
 
-	// Write type and scope
-	sb.WriteString(msg.Type)
-	if msg.Scope != "" {
-		sb.WriteString("(")
-		sb.WriteString(msg.Scope)
-		sb.WriteString(")")
+	// Check if this is a summarized diff
+	isSummary := strings.HasPrefix(changes, "Changed files summary:")
+
+	var plan chunkPlan
+	if !isSummary {
+		plan = planChunks(changes, maxChunkSize, maxChunks)
 	}
-	if msg.Breaking {
-		sb.WriteString("!")
+	if len(plan.Chunks) == 0 && len(plan.Skipped) == 0 {
+		// Not a diff: split by lines, keeping the beginning unless it is a summary
+		plan.Chunks = splitLines(changes, maxChunkSize)
+		if len(plan.Chunks) > maxChunks && !isSummary {
+			plan.Chunks = plan.Chunks[:maxChunks]
+			plan.Truncated = true
+		}
 	}
-	sb.WriteString(": ")
-	sb.WriteString(msg.Subject)
-
-	// Add body if present
-	if msg.Body != "" {
-		sb.WriteString("\n\n")
-		sb.WriteString(msg.Body)
+	if len(plan.Chunks) == 0 {
+		// Only noise changed: its summary is all there is to analyze
+		plan.Chunks = []string{""}
 	}
 
-	// Add breaking change marker if needed
-	if msg.Breaking {
-		sb.WriteString("\n\nBREAKING CHANGE: This commit introduces breaking changes")
+	logging.Debug("planned commit chunks", "chunks", len(plan.Chunks), "summarized", len(plan.Skipped), "truncated", plan.Truncated)
+
+	// With a single chunk the summarized files are analyzed along with it
+	if len(plan.Chunks) == 1 && len(plan.Skipped) > 0 {
+		plan.Chunks[0] += "\nOther changed files, with their line counts:\n" + plan.SkippedSummary() + "\n"
 	}
 
-	// Add issue references
-	if len(msg.IssueRefs) > 0 {
-		sb.WriteString("\n\nRefs: ")
-		sb.WriteString(strings.Join(msg.IssueRefs, ", "))
+	// Analyze each chunk
+	analyses := make([]commitDraft, len(plan.Chunks))
+	errs := utils.ForEach(ctx, len(plan.Chunks), config.DefaultConcurrency, func(ctx context.Context, i int) error {
+		analysis, err := ai.analyzeChunk(ctx, plan.Chunks[i], i+1, len(plan.Chunks), isSummary)
+		if err != nil {
+			return err
+		}
+		analyses[i] = analysis
+		return nil
+	})
+	if err := utils.FirstError(errs); err != nil {
+		return nil, plan, err
 	}
+	return analyses, plan, nil
+}
+
+// FormatCommitMessage formats a CommitMessage with the formatter, the
+// issue refs given to WithIssueRefs included
+func (ai *CommitAI) FormatCommitMessage(msg models.CommitMessage) (string, error) {
+	return ai.format(commitmsg.Message{CommitMessage: msg})
+}
 
-	// Add co-authors
-	for _, author := range msg.CoAuthors {
-		sb.WriteString("\n\nCo-authored-by: ")
-		sb.WriteString(author)
+// ReformatCommitMessage renders a Conventional Commits message written by
+// the AI with the formatter, the issue refs included. The trailers the AI
+// wrote are dropped, apart from its breaking change description. Messages
+// that do not parse are returned as they are.
+func (ai *CommitAI) ReformatCommitMessage(text string) (string, error) {
+	msg, err := commitmsg.Parse(text)
+	if err != nil {
+		return strings.TrimSpace(text), nil
 	}
+	msg.IssueRefs, msg.CoAuthors, msg.Footers = nil, nil, nil
+	return ai.format(msg)
+}
 
-	return sb.String()
-}
\ No newline at end of file
+func (ai *CommitAI) format(msg commitmsg.Message) (string, error) {
+	for _, ref := range ai.issueRefs {
+		if !slices.Contains(msg.IssueRefs, ref) {
+			msg.IssueRefs = append(msg.IssueRefs, ref)
+		}
+	}
+	return ai.formatter.Format(msg)
+}
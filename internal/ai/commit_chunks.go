@@ -0,0 +1,267 @@
+package ai
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/baudevs/yolo.baudevs.com/internal/git"
+)
+
+// File kinds, from the most to the least telling about a commit. Files of
+// the noise kinds are described by their line counts only.
+const (
+	kindSource    = "source"
+	kindTest      = "test"
+	kindConfig    = "config"
+	kindDocs      = "docs"
+	kindDeleted   = "deleted"
+	kindBinary    = "binary"
+	kindLockfile  = "lockfile"
+	kindGenerated = "generated"
+	kindVendored  = "vendored"
+)
+
+// kindRank orders files within the diff budget; zero means noise
+var kindRank = map[string]int{
+	kindSource: 4,
+	kindTest:   3,
+	kindConfig: 2,
+	kindDocs:   1,
+}
+
+var lockfiles = map[string]bool{
+	"go.sum": true, "package-lock.json": true, "yarn.lock": true, "pnpm-lock.yaml": true,
+	"Cargo.lock": true, "poetry.lock": true, "Pipfile.lock": true, "Gemfile.lock": true,
+	"composer.lock": true, "mix.lock": true, "pubspec.lock": true, "Podfile.lock": true,
+}
+
+var vendoredDirs = []string{"vendor/", "node_modules/", "third_party/", "bower_components/"}
+
+var generatedSuffixes = []string{
+	".pb.go", "_gen.go", ".gen.go", "_generated.go", "_string.go", ".pb.gw.go",
+	".min.js", ".min.css", ".map", "_pb2.py", ".g.dart", ".snap",
+}
+
+var generatedMarkers = []string{"Code generated", "DO NOT EDIT", "@generated", "autogenerated"}
+
+var configExtensions = map[string]bool{
+	".yml": true, ".yaml": true, ".json": true, ".toml": true, ".ini": true, ".cfg": true,
+	".conf": true, ".env": true, ".xml": true, ".properties": true, ".mod": true,
+}
+
+var docsExtensions = map[string]bool{".md": true, ".txt": true, ".rst": true, ".adoc": true}
+
+// changedFile is a file diff with its kind and size
+type changedFile struct {
+	diff    git.FileDiff
+	kind    string
+	added   int
+	deleted int
+}
+
+// summary describes the file by its line counts, e.g. "go.sum (lockfile, +12 -3)"
+func (f changedFile) summary() string {
+	if f.kind == kindBinary {
+		return f.diff.Path + " (binary)"
+	}
+	return fmt.Sprintf("%s (%s, +%d -%d)", f.diff.Path, f.kind, f.added, f.deleted)
+}
+
+// classifyFile tells what kind of file a diff touches
+func classifyFile(f git.FileDiff) string {
+	p := f.Path
+	base := path.Base(p)
+	ext := strings.ToLower(path.Ext(p))
+
+	switch {
+	case f.Binary:
+		return kindBinary
+	case f.Deleted:
+		return kindDeleted
+	case lockfiles[base]:
+		return kindLockfile
+	}
+	for _, dir := range vendoredDirs {
+		if strings.HasPrefix(p, dir) || strings.Contains(p, "/"+dir) {
+			return kindVendored
+		}
+	}
+	for _, suffix := range generatedSuffixes {
+		if strings.HasSuffix(base, suffix) {
+			return kindGenerated
+		}
+	}
+	if isGenerated(f) {
+		return kindGenerated
+	}
+
+	switch {
+	case strings.HasSuffix(base, "_test.go") || strings.Contains(base, ".test.") ||
+		strings.Contains(base, ".spec.") || strings.HasPrefix(base, "test_") ||
+		strings.Contains(p, "/testdata/") || strings.HasPrefix(p, "testdata/"):
+		return kindTest
+	case docsExtensions[ext] || strings.HasPrefix(p, "docs/"):
+		return kindDocs
+	case configExtensions[ext] || strings.HasPrefix(base, "."):
+		return kindConfig
+	}
+	return kindSource
+}
+
+// isGenerated looks for a generated code marker in the first lines of the file
+func isGenerated(f git.FileDiff) bool {
+	if len(f.Hunks) == 0 || f.Hunks[0].NewStart > 10 {
+		return false
+	}
+	lines := f.Hunks[0].Lines
+	if len(lines) > 10 {
+		lines = lines[:10]
+	}
+	for _, line := range lines {
+		for _, marker := range generatedMarkers {
+			if strings.Contains(line, marker) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// rankFiles classifies the files of a diff and sorts them by significance,
+// then by size, so the most telling changes come first
+func rankFiles(diffs []git.FileDiff) []changedFile {
+	files := make([]changedFile, len(diffs))
+	for i, d := range diffs {
+		added, deleted := d.Stats()
+		files[i] = changedFile{diff: d, kind: classifyFile(d), added: added, deleted: deleted}
+	}
+	sort.SliceStable(files, func(i, j int) bool {
+		ri, rj := kindRank[files[i].kind], kindRank[files[j].kind]
+		if ri != rj {
+			return ri > rj
+		}
+		return files[i].added+files[i].deleted > files[j].added+files[j].deleted
+	})
+	return files
+}
+
+// chunkPlan is how a diff is sent for analysis: chunks of whole hunks, and
+// the files summarized by their line counts
+type chunkPlan struct {
+	Chunks    []string
+	Skipped   []string
+	Truncated bool // Some files were left out for lack of room, not as noise
+}
+
+// SkippedSummary lists the summarized files for a prompt
+func (p chunkPlan) SkippedSummary() string {
+	if len(p.Skipped) == 0 {
+		return ""
+	}
+	return "- " + strings.Join(p.Skipped, "\n- ")
+}
+
+// planChunks splits a diff at file and hunk boundaries into at most maxChunks
+// chunks of about maxChunkSize bytes, most significant files first. Noise
+// (lockfiles, generated, vendored, binary and deleted files) and whatever
+// does not fit are summarized by their line counts instead.
+func planChunks(diff string, maxChunkSize, maxChunks int) chunkPlan {
+	var plan chunkPlan
+	var current strings.Builder
+
+	full := func() bool { return len(plan.Chunks) >= maxChunks }
+	flush := func() {
+		if current.Len() > 0 {
+			plan.Chunks = append(plan.Chunks, current.String())
+			current.Reset()
+		}
+	}
+
+	for _, f := range rankFiles(git.ParseDiff(diff)) {
+		if kindRank[f.kind] == 0 {
+			plan.Skipped = append(plan.Skipped, f.summary())
+			continue
+		}
+		if full() {
+			plan.Skipped = append(plan.Skipped, f.summary())
+			plan.Truncated = true
+			continue
+		}
+
+		header := f.diff.Header
+		if len(f.diff.Hunks) == 0 {
+			if current.Len()+len(header) > maxChunkSize {
+				flush()
+			}
+			current.WriteString(header)
+			continue
+		}
+
+		written := false
+		for i, h := range f.diff.Hunks {
+			hunk := fitHunk(h, maxChunkSize-len(header))
+			if current.Len()+len(header)+len(hunk) > maxChunkSize {
+				flush()
+				written = false
+				if full() {
+					plan.Truncated = true
+					if i == 0 {
+						plan.Skipped = append(plan.Skipped, f.summary())
+						break
+					}
+					rest := changedFile{diff: git.FileDiff{Path: f.diff.Path, Hunks: f.diff.Hunks[i:]}, kind: f.kind}
+					rest.added, rest.deleted = rest.diff.Stats()
+					plan.Skipped = append(plan.Skipped, fmt.Sprintf("%s, %d of %d hunks not shown", rest.summary(), len(rest.diff.Hunks), len(f.diff.Hunks)))
+					break
+				}
+			}
+			if !written {
+				current.WriteString(header)
+				written = true
+			}
+			current.WriteString(hunk)
+		}
+	}
+	flush()
+
+	return plan
+}
+
+// fitHunk renders a hunk, cutting its lines when it is bigger than size
+func fitHunk(h git.Hunk, size int) string {
+	text := h.String()
+	if len(text) <= size {
+		return text
+	}
+
+	var sb strings.Builder
+	sb.WriteString(h.Header + "\n")
+	for i, line := range h.Lines {
+		if sb.Len()+len(line)+1 > size-64 {
+			fmt.Fprintf(&sb, "... %d more lines in this hunk\n", len(h.Lines)-i)
+			break
+		}
+		sb.WriteString(line + "\n")
+	}
+	return sb.String()
+}
+
+// splitLines splits text that is not a diff into chunks of whole lines
+func splitLines(text string, maxChunkSize int) []string {
+	var chunks []string
+	var current strings.Builder
+	for _, line := range strings.Split(text, "\n") {
+		if current.Len()+len(line)+1 > maxChunkSize && current.Len() > 0 {
+			chunks = append(chunks, current.String())
+			current.Reset()
+		}
+		current.WriteString(line)
+		current.WriteString("\n")
+	}
+	if current.Len() > 0 {
+		chunks = append(chunks, current.String())
+	}
+	return chunks
+}
@@ -0,0 +1,30 @@
+package ai
+
+import (
+	"strings"
+	"sync"
+)
+
+var (
+	styleMu     sync.Mutex
+	commitStyle PromptData
+)
+
+// SetCommitStyle sets the commit conventions of the repository, appended to
+// commit prompts through the commit.style prompt. Empty conventions clear it.
+func SetCommitStyle(conventions, examples string) {
+	styleMu.Lock()
+	defer styleMu.Unlock()
+	if strings.TrimSpace(conventions) == "" {
+		commitStyle = nil
+		return
+	}
+	commitStyle = PromptData{"Conventions": conventions, "Examples": examples}
+}
+
+// CommitStyle returns the data of the commit.style prompt, or nil when no style is set
+func CommitStyle() PromptData {
+	styleMu.Lock()
+	defer styleMu.Unlock()
+	return commitStyle
+}
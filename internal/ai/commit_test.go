@@ -0,0 +1,93 @@
+package ai
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+const testDiff = `diff --git a/parser.go b/parser.go
+index 1111111..2222222 100644
+--- a/parser.go
++++ b/parser.go
+@@ -1,3 +1,6 @@
+ func Parse(input string) (*Node, error) {
++	if input == "" {
++		return nil, ErrEmpty
++	}
+ 	return parse(input)
+ }
+`
+
+func TestGenerateCommitMessageWithMock(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	SetCommitStyle("", "")
+
+	mock, err := NewMockProvider(MockFixture{
+		Match:    `Analyze the following Git changes`,
+		Response: `{"type": "fix", "scope": "parser", "subject": "reject empty input", "body": "Parse returns ErrEmpty for an empty string."}`,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	commitAI := NewCommitAI(NewClientWithProvider(mock)).WithIssueRefs("T012")
+	message, truncated, err := commitAI.GenerateCommitMessage(context.Background(), testDiff)
+	if err != nil {
+		t.Fatalf("GenerateCommitMessage: %v", err)
+	}
+	if truncated {
+		t.Error("a small diff should not be truncated")
+	}
+
+	want := "fix(parser): reject empty input\n\nParse returns ErrEmpty for an empty string.\n\nRefs: T012"
+	if message != want {
+		t.Errorf("message = %q, want %q", message, want)
+	}
+
+	requests := mock.Requests()
+	if len(requests) != 1 {
+		t.Fatalf("got %d requests, want 1 for a single chunk", len(requests))
+	}
+	if prompt := promptText(requests[0].Messages); !strings.Contains(prompt, "return nil, ErrEmpty") {
+		t.Errorf("the prompt does not contain the diff:\n%s", prompt)
+	}
+}
+
+func TestCommitMessageTrailersNotFromModel(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	SetCommitStyle("", "")
+
+	mock, err := NewMockProvider(MockFixture{
+		Match:    `Analyze the following Git changes`,
+		Response: `{"type": "fix", "subject": "reject empty input", "issue_refs": ["#999"], "co_authors": ["mock co_authors"]}`,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	commitAI := NewCommitAI(NewClientWithProvider(mock)).WithIssueRefs("T012")
+	message, _, err := commitAI.GenerateCommitMessage(context.Background(), testDiff)
+	if err != nil {
+		t.Fatalf("GenerateCommitMessage: %v", err)
+	}
+	if want := "fix: reject empty input\n\nRefs: T012"; message != want {
+		t.Errorf("message = %q, want %q", message, want)
+	}
+
+	schema := SchemaFor(commitDraft{})
+	for _, field := range []string{"issue_refs", "co_authors"} {
+		if _, ok := schema.Properties[field]; ok {
+			t.Errorf("the commit message schema asks the model for %s", field)
+		}
+	}
+
+	revised := "feat!: drop the v1 API\n\nBREAKING CHANGE: v1 clients must upgrade\nRefs: #999\nCo-authored-by: mock co_authors\nSigned-off-by: Someone <someone@example.com>"
+	message, err = commitAI.ReformatCommitMessage(revised)
+	if err != nil {
+		t.Fatalf("ReformatCommitMessage: %v", err)
+	}
+	if want := "feat!: drop the v1 API\n\nBREAKING CHANGE: v1 clients must upgrade\nRefs: T012"; message != want {
+		t.Errorf("reformatted message = %q, want %q", message, want)
+	}
+}
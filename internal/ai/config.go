@@ -135,12 +135,10 @@ func (c *Config) GetAPIKey(provider string) string {
 	return ""
 }
 
-// createDefaultConfig creates a default AI configuration
-func createDefaultConfig() (*Config, error) {
-	config := &Config{
-		Model: "gpt-4",
-		Prompts: map[string]string{
-			"commit": `Analyze these changes and generate a conventional commit message:
+// legacyDefaultPrompts are the Sprintf style prompts new configs got before
+// the prompt registry. Configs still holding them keep the registry's prompts.
+var legacyDefaultPrompts = map[string]string{
+	"commit": `Analyze these changes and generate a conventional commit message:
 
 %s
 
@@ -152,7 +150,7 @@ The commit message should follow the Conventional Commits specification and incl
 5. Optional footer with issue references
 
 Keep it clear and concise.`,
-			"error": `Error context: %s
+	"error": `Error context: %s
 Error message: %v
 
 Please analyze this error and provide:
@@ -162,7 +160,7 @@ Please analyze this error and provide:
 4. Any preventive measures for the future
 
 Format the response in a clear, concise way.`,
-			"ask": `You are a helpful programming assistant. Please provide a 3-step solution to this question:
+	"ask": `You are a helpful programming assistant. Please provide a 3-step solution to this question:
 %s
 
 Format your response as:
@@ -173,7 +171,12 @@ Format your response as:
 3. [Third step]"
 
 Keep each step concise and practical.`,
-		},
+}
+
+// createDefaultConfig creates a default AI configuration
+func createDefaultConfig() (*Config, error) {
+	config := &Config{
+		Model:   "gpt-4",
 		APIKeys: make(map[string]string),
 	}
 
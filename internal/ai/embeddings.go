@@ -0,0 +1,123 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"unicode"
+
+	"github.com/sashabaranov/go-openai"
+)
+
+// EmbeddingModel is the model used to embed text
+const EmbeddingModel = openai.AdaEmbeddingV2
+
+// mockEmbeddingDimensions is the size of the vectors returned by the mock provider
+const mockEmbeddingDimensions = 256
+
+// ErrEmbeddingsUnsupported is returned when the provider cannot embed text
+var ErrEmbeddingsUnsupported = errors.New("provider does not support embeddings")
+
+// Embedder is implemented by providers that can embed text.
+// *openai.Client satisfies it, so do the metered and mock providers.
+type Embedder interface {
+	CreateEmbeddings(ctx context.Context, conv openai.EmbeddingRequestConverter) (openai.EmbeddingResponse, error)
+}
+
+// Embed returns one embedding vector per text, in order
+func (c *Client) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	embedder, ok := c.client.(Embedder)
+	if !ok {
+		return nil, ErrEmbeddingsUnsupported
+	}
+
+	resp, err := embedder.CreateEmbeddings(ctx, openai.EmbeddingRequestStrings{
+		Input: texts,
+		Model: EmbeddingModel,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create embeddings: %w", err)
+	}
+	if len(resp.Data) != len(texts) {
+		return nil, fmt.Errorf("expected %d embeddings, got %d", len(texts), len(resp.Data))
+	}
+
+	vectors := make([][]float32, len(texts))
+	for _, e := range resp.Data {
+		if e.Index < 0 || e.Index >= len(vectors) {
+			return nil, fmt.Errorf("embedding index %d out of range", e.Index)
+		}
+		vectors[e.Index] = e.Embedding
+	}
+
+	return vectors, nil
+}
+
+func (p *meteredProvider) CreateEmbeddings(ctx context.Context, conv openai.EmbeddingRequestConverter) (openai.EmbeddingResponse, error) {
+	embedder, ok := p.next.(Embedder)
+	if !ok {
+		return openai.EmbeddingResponse{}, ErrEmbeddingsUnsupported
+	}
+
+	resp, err := embedder.CreateEmbeddings(ctx, conv)
+	if err != nil {
+		return resp, err
+	}
+
+	if !p.mock {
+		RecordUsage(conv.Convert().Model.String(), resp.Usage)
+	}
+	return resp, nil
+}
+
+// CreateEmbeddings implements Embedder with hashed bag-of-words vectors,
+// so texts sharing words are close to each other
+func (m *MockProvider) CreateEmbeddings(ctx context.Context, conv openai.EmbeddingRequestConverter) (openai.EmbeddingResponse, error) {
+	if err := ctx.Err(); err != nil {
+		return openai.EmbeddingResponse{}, err
+	}
+
+	req := conv.Convert()
+	texts, ok := req.Input.([]string)
+	if !ok {
+		return openai.EmbeddingResponse{}, fmt.Errorf("mock provider only embeds strings")
+	}
+
+	resp := openai.EmbeddingResponse{Object: "list", Model: req.Model}
+	for i, text := range texts {
+		resp.Data = append(resp.Data, openai.Embedding{
+			Object:    "embedding",
+			Embedding: mockEmbedding(text),
+			Index:     i,
+		})
+		resp.Usage.PromptTokens += len(text) / 4
+	}
+	resp.Usage.TotalTokens = resp.Usage.PromptTokens
+
+	return resp, nil
+}
+
+func mockEmbedding(text string) []float32 {
+	vector := make([]float32, mockEmbeddingDimensions)
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for _, word := range words {
+		vector[promptHash(word)%mockEmbeddingDimensions]++
+	}
+
+	var norm float64
+	for _, v := range vector {
+		norm += float64(v * v)
+	}
+	if norm > 0 {
+		norm = math.Sqrt(norm)
+		for i := range vector {
+			vector[i] = float32(float64(vector[i]) / norm)
+		}
+	}
+
+	return vector
+}
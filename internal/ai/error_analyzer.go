@@ -2,7 +2,6 @@ package ai
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"strings"
 
@@ -10,55 +9,64 @@ import (
 )
 
 type ErrorAnalysis struct {
-	Problem     string   `json:"problem"`
-	Explanation string   `json:"explanation"`
-	Solutions   []string `json:"solutions"`
+	Problem     string   `json:"problem" description:"Brief description of the issue"`
+	Explanation string   `json:"explanation" description:"User-friendly explanation of what went wrong"`
+	Solutions   []string `json:"solutions" description:"Step-by-step solutions"`
 }
 
 type ErrorAnalyzer struct {
-	client *openai.Client
+	client Provider
+	model  string
 }
 
 func NewErrorAnalyzer(apiKey string) *ErrorAnalyzer {
 	return &ErrorAnalyzer{
-		client: openai.NewClient(apiKey),
+		client: NewProvider(apiKey),
+		model:  DefaultModel,
 	}
 }
 
-func (ea *ErrorAnalyzer) AnalyzeError(err error, contextStr string) (*ErrorAnalysis, error) {
-	prompt := fmt.Sprintf(`Analyze the following error in the context of a Git operation and provide a helpful explanation and solutions.
-Context: %s
-Error: %v
+// NewErrorAnalyzerWithProvider creates an error analyzer backed by the given provider
+func NewErrorAnalyzerWithProvider(p Provider) *ErrorAnalyzer {
+	return &ErrorAnalyzer{
+		client: p,
+		model:  DefaultModel,
+	}
+}
 
-Respond with a JSON object containing:
-{
-  "problem": "brief description of the issue",
-  "explanation": "user-friendly explanation of what went wrong",
-  "solutions": ["array of step-by-step solutions"]
-}`, contextStr, err)
+// WithModel returns an analyzer asking another model, e.g. the configured one
+func (ea *ErrorAnalyzer) WithModel(model string) *ErrorAnalyzer {
+	return &ErrorAnalyzer{client: ea.client, model: model}
+}
 
-	resp, err := ea.client.CreateChatCompletion(
+func (ea *ErrorAnalyzer) AnalyzeError(err error, contextStr string) (*ErrorAnalysis, error) {
+	prompt, renderErr := RenderPrompt("error.analyze", PromptData{
+		"Context": contextStr,
+		"Error":   err,
+	})
+	if renderErr != nil {
+		return nil, renderErr
+	}
+
+	analysis, err := GenerateStructured[ErrorAnalysis](
 		context.Background(),
-		openai.ChatCompletionRequest{
-			Model: openai.GPT3Dot5Turbo,
+		ea.client,
+		StructuredRequest{
+			Model: ea.model,
 			Messages: []openai.ChatCompletionMessage{
 				{
 					Role:    openai.ChatMessageRoleUser,
 					Content: prompt,
 				},
 			},
+			Name:        "report_error_analysis",
+			Description: "Report the problem, an explanation and step-by-step solutions",
 		},
 	)
-
 	if err != nil {
 		return nil, fmt.Errorf("failed to analyze error: %w", err)
 	}
 
-	var analysis ErrorAnalysis
-	if err := json.Unmarshal([]byte(resp.Choices[0].Message.Content), &analysis); err != nil {
-		return nil, fmt.Errorf("failed to parse AI response: %w", err)
-	}
-
 	return &analysis, nil
 }
 
@@ -0,0 +1,72 @@
+package ai
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/baudevs/yolo.baudevs.com/internal/config"
+)
+
+// languageNames spells out common language codes for the prompts
+var languageNames = map[string]string{
+	"ca": "Catalan",
+	"de": "German",
+	"en": "English",
+	"es": "Spanish",
+	"fr": "French",
+	"it": "Italian",
+	"ja": "Japanese",
+	"ko": "Korean",
+	"nl": "Dutch",
+	"pl": "Polish",
+	"pt": "Portuguese",
+	"ru": "Russian",
+	"sv": "Swedish",
+	"zh": "Chinese",
+}
+
+var (
+	languageMu       sync.Mutex
+	languageOverride string
+)
+
+// SetContentLanguage overrides the project's content language for this process, e.g. from --lang
+func SetContentLanguage(lang string) {
+	languageMu.Lock()
+	defer languageMu.Unlock()
+	languageOverride = strings.TrimSpace(lang)
+}
+
+// ContentLanguage returns the language generated content is written in:
+// the override, or else the project's content_language, or "" for the default
+func ContentLanguage() string {
+	languageMu.Lock()
+	lang := languageOverride
+	languageMu.Unlock()
+	if lang != "" {
+		return lang
+	}
+
+	if settings, err := config.LoadProjectSettings(); err == nil {
+		return strings.TrimSpace(settings.ContentLanguage)
+	}
+	return ""
+}
+
+// LanguageName returns the English name of a language code such as "es",
+// or the value itself when it is already a name or an unknown code
+func LanguageName(lang string) string {
+	code := strings.ToLower(lang)
+	if i := strings.IndexAny(code, "-_"); i > 0 {
+		code = code[:i] // "es-MX" is Spanish too
+	}
+	if name, ok := languageNames[code]; ok {
+		return name
+	}
+	return lang
+}
+
+// isEnglish reports whether lang needs no instruction, English being the prompts' language
+func isEnglish(lang string) bool {
+	return lang == "" || LanguageName(lang) == "English" || strings.EqualFold(lang, "english")
+}
@@ -0,0 +1,330 @@
+package ai
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/baudevs/yolo.baudevs.com/internal/config"
+	"github.com/sashabaranov/go-openai"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// MockModel is the model name reported by the mock provider
+	MockModel = "mock"
+
+	mockFixturesFileName = "mock_fixtures.yml"
+)
+
+//go:embed mock_fixtures.yml
+var defaultMockFixtures []byte
+
+// MockFixture maps a prompt pattern to a canned response
+type MockFixture struct {
+	Match    string `yaml:"match"`    // Regular expression matched against the whole prompt
+	Response string `yaml:"response"` // Message content, or function arguments when functions are requested
+
+	re *regexp.Regexp
+}
+
+// mockFixtureFile is the on-disk fixtures format
+type mockFixtureFile struct {
+	Fixtures []MockFixture `yaml:"fixtures"`
+}
+
+// MockProvider answers chat completions offline from fixtures.
+// Prompts without a matching fixture get a deterministic generated answer,
+// so the same prompt always yields the same response.
+type MockProvider struct {
+	mu       sync.Mutex
+	fixtures []MockFixture
+	requests []openai.ChatCompletionRequest
+}
+
+// NewMockProvider creates a mock provider answering from the given fixtures
+func NewMockProvider(fixtures ...MockFixture) (*MockProvider, error) {
+	for i := range fixtures {
+		re, err := regexp.Compile(fixtures[i].Match)
+		if err != nil {
+			return nil, fmt.Errorf("invalid mock fixture pattern %q: %w", fixtures[i].Match, err)
+		}
+		fixtures[i].re = re
+	}
+
+	return &MockProvider{fixtures: fixtures}, nil
+}
+
+// LoadMockFixtures reads fixtures from a YAML file
+func LoadMockFixtures(path string) ([]MockFixture, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read mock fixtures: %w", err)
+	}
+
+	return parseMockFixtures(data)
+}
+
+func parseMockFixtures(data []byte) ([]MockFixture, error) {
+	var file mockFixtureFile
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse mock fixtures: %w", err)
+	}
+	return file.Fixtures, nil
+}
+
+// Requests returns every request the provider has received, in order
+func (m *MockProvider) Requests() []openai.ChatCompletionRequest {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]openai.ChatCompletionRequest(nil), m.requests...)
+}
+
+// CreateChatCompletion implements Provider
+func (m *MockProvider) CreateChatCompletion(ctx context.Context, req openai.ChatCompletionRequest) (openai.ChatCompletionResponse, error) {
+	if err := ctx.Err(); err != nil {
+		return openai.ChatCompletionResponse{}, err
+	}
+
+	m.mu.Lock()
+	m.requests = append(m.requests, req)
+	m.mu.Unlock()
+
+	prompt := promptText(req.Messages)
+	response, matched := m.match(prompt)
+
+	msg := openai.ChatCompletionMessage{Role: openai.ChatMessageRoleAssistant}
+	finishReason := openai.FinishReasonStop
+	if calls, ok := mockToolCalls(req, response, matched); ok {
+		msg.ToolCalls = calls
+		finishReason = openai.FinishReasonToolCalls
+	} else if len(req.Functions) > 0 {
+		if !matched {
+			response = mockArguments(req.Functions[0], prompt)
+		}
+		msg.FunctionCall = &openai.FunctionCall{
+			Name:      req.Functions[0].Name,
+			Arguments: response,
+		}
+	} else if isJSONMode(req) && !matched {
+		msg.Content = mockJSON(req.Messages, prompt)
+		response = msg.Content
+	} else {
+		if !matched {
+			response = mockText(prompt)
+		}
+		msg.Content = response
+	}
+
+	// Roughly four characters per token, which is close enough for ledger tests
+	usage := openai.Usage{
+		PromptTokens:     len(prompt) / 4,
+		CompletionTokens: len(response) / 4,
+	}
+	usage.TotalTokens = usage.PromptTokens + usage.CompletionTokens
+
+	return openai.ChatCompletionResponse{
+		ID:    fmt.Sprintf("mock-%08x", promptHash(prompt)),
+		Model: MockModel,
+		Choices: []openai.ChatCompletionChoice{
+			{
+				Message:      msg,
+				FinishReason: finishReason,
+			},
+		},
+		Usage: usage,
+	}, nil
+}
+
+// match returns the response of the first fixture matching the prompt
+func (m *MockProvider) match(prompt string) (string, bool) {
+	for _, f := range m.fixtures {
+		if f.re.MatchString(prompt) {
+			return strings.TrimSpace(f.Response), true
+		}
+	}
+	return "", false
+}
+
+// mockToolCallsResponse is the fixture response format for tool calls
+type mockToolCallsResponse struct {
+	ToolCalls []struct {
+		Name      string          `json:"name"`
+		Arguments json.RawMessage `json:"arguments"`
+	} `json:"tool_calls"`
+}
+
+// mockToolCalls turns a matching fixture into tool calls when tools are offered.
+// Only a user message triggers them: once tool results come back the mock answers
+// in text, so agent loops always terminate.
+func mockToolCalls(req openai.ChatCompletionRequest, response string, matched bool) ([]openai.ToolCall, bool) {
+	if len(req.Tools) == 0 || !matched || len(req.Messages) == 0 {
+		return nil, false
+	}
+	if req.Messages[len(req.Messages)-1].Role != openai.ChatMessageRoleUser {
+		return nil, false
+	}
+
+	var parsed mockToolCallsResponse
+	if err := json.Unmarshal([]byte(response), &parsed); err != nil || len(parsed.ToolCalls) == 0 {
+		return nil, false
+	}
+
+	calls := make([]openai.ToolCall, len(parsed.ToolCalls))
+	for i, call := range parsed.ToolCalls {
+		arguments := string(call.Arguments)
+		if arguments == "" {
+			arguments = "{}"
+		}
+		calls[i] = openai.ToolCall{
+			ID:   fmt.Sprintf("call_%d", i+1),
+			Type: openai.ToolTypeFunction,
+			Function: openai.FunctionCall{
+				Name:      call.Name,
+				Arguments: arguments,
+			},
+		}
+	}
+	return calls, true
+}
+
+// MockEnabled reports whether the mock provider is selected,
+// either by YOLO_AI_PROVIDER=mock or by `ai.provider: mock` in the config.
+func MockEnabled() bool {
+	if provider := os.Getenv("YOLO_AI_PROVIDER"); provider != "" {
+		return strings.EqualFold(provider, MockModel)
+	}
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return false
+	}
+	return strings.EqualFold(cfg.AI.Provider, MockModel)
+}
+
+// newConfiguredMockProvider builds the mock provider used by the CLI.
+// User fixtures are tried before the defaults embedded in the binary.
+func newConfiguredMockProvider() (*MockProvider, error) {
+	var fixtures []MockFixture
+
+	path := os.Getenv("YOLO_MOCK_FIXTURES")
+	if path == "" {
+		if configDir, err := getConfigDir(); err == nil {
+			path = filepath.Join(configDir, mockFixturesFileName)
+		}
+	}
+	if path != "" {
+		if _, err := os.Stat(path); err == nil {
+			userFixtures, err := LoadMockFixtures(path)
+			if err != nil {
+				return nil, err
+			}
+			fixtures = append(fixtures, userFixtures...)
+		}
+	}
+
+	defaults, err := parseMockFixtures(defaultMockFixtures)
+	if err != nil {
+		return nil, err
+	}
+	fixtures = append(fixtures, defaults...)
+
+	return NewMockProvider(fixtures...)
+}
+
+// promptText flattens the conversation into the text fixtures are matched against
+func promptText(messages []openai.ChatCompletionMessage) string {
+	var sb strings.Builder
+	for _, msg := range messages {
+		sb.WriteString(msg.Content)
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+func promptHash(prompt string) uint32 {
+	h := fnv.New32a()
+	h.Write([]byte(prompt))
+	return h.Sum32()
+}
+
+// mockText generates a deterministic plain-text answer for a prompt
+func mockText(prompt string) string {
+	subject := strings.TrimSpace(prompt)
+	if i := strings.Index(subject, "\n"); i != -1 {
+		subject = subject[:i]
+	}
+	if len(subject) > 80 {
+		subject = subject[:80] + "..."
+	}
+
+	return fmt.Sprintf(`Mock response %08x
+
+This content was generated offline by the mock AI provider for:
+%s`, promptHash(prompt), subject)
+}
+
+// mockArguments generates deterministic function arguments that satisfy the function's schema
+func mockArguments(fn openai.FunctionDefinition, prompt string) string {
+	var schema JSONSchemaDefinition
+	if data, err := json.Marshal(fn.Parameters); err == nil {
+		_ = json.Unmarshal(data, &schema)
+	}
+
+	data, err := json.Marshal(mockValue(schema, fn.Name, promptHash(prompt)))
+	if err != nil {
+		return "{}"
+	}
+	return string(data)
+}
+
+// isJSONMode reports whether a request asks for a JSON object as content
+func isJSONMode(req openai.ChatCompletionRequest) bool {
+	return req.ResponseFormat != nil && req.ResponseFormat.Type == openai.ChatCompletionResponseFormatTypeJSONObject
+}
+
+// mockJSON generates a JSON object from the schema GenerateStructured gives
+// in the system prompt of JSON mode requests
+func mockJSON(messages []openai.ChatCompletionMessage, prompt string) string {
+	var schema JSONSchemaDefinition
+	for _, msg := range messages {
+		if msg.Role == openai.ChatMessageRoleSystem {
+			if err := json.Unmarshal([]byte(extractJSON(msg.Content)), &schema); err == nil {
+				break
+			}
+		}
+	}
+	return mockArguments(openai.FunctionDefinition{Name: "json", Parameters: schema}, prompt)
+}
+
+func mockValue(schema JSONSchemaDefinition, name string, seed uint32) interface{} {
+	switch schema.Type {
+	case JSONSchemaTypeString:
+		if len(schema.Enum) > 0 {
+			return schema.Enum[0]
+		}
+		return fmt.Sprintf("mock %s %08x", name, seed)
+	case JSONSchemaTypeBoolean:
+		return false
+	case JSONSchemaTypeInteger, JSONSchemaTypeNumber:
+		return 1
+	case JSONSchemaTypeArray:
+		if schema.Items == nil {
+			return []interface{}{}
+		}
+		return []interface{}{mockValue(*schema.Items, name, seed)}
+	default:
+		obj := make(map[string]interface{}, len(schema.Properties))
+		for prop, def := range schema.Properties {
+			obj[prop] = mockValue(def, prop, seed)
+		}
+		return obj
+	}
+}
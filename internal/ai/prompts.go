@@ -0,0 +1,339 @@
+package ai
+
+import (
+	"bytes"
+	_ "embed"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"sync"
+	"text/template"
+
+	"github.com/baudevs/yolo.baudevs.com/internal/config"
+	"github.com/baudevs/yolo.baudevs.com/internal/logging"
+	"gopkg.in/yaml.v3"
+)
+
+// Prompt sources, from lowest to highest precedence
+const (
+	PromptSourceDefault = "default"
+	PromptSourceGlobal  = "global"
+	PromptSourceProject = "project"
+)
+
+// ProjectPromptsPath is the per-project prompt overrides file written by yolo init
+var ProjectPromptsPath = filepath.Join("yolo", "settings", "prompts.yml")
+
+//go:embed prompts.yml
+var defaultPrompts []byte
+
+// Prompt is a named text/template sent to the AI
+type Prompt struct {
+	Name        string `yaml:"name"`
+	Description string `yaml:"description,omitempty"`
+	Template    string `yaml:"template"`
+	Content     bool   `yaml:"content,omitempty"`      // Generates project content, written in the content language
+	CommitStyle bool   `yaml:"commit_style,omitempty"` // Writes commit messages, in the repository's commit style
+	Source      string `yaml:"-"`
+}
+
+// PromptData holds the values a prompt template is rendered with
+type PromptData map[string]interface{}
+
+// promptFile is the on-disk prompts format
+type promptFile struct {
+	Prompts []Prompt `yaml:"prompts"`
+}
+
+// PromptRegistry resolves prompts by name across all override layers
+type PromptRegistry struct {
+	layers map[string][]Prompt // Every definition of a prompt, lowest precedence first
+}
+
+var (
+	registryOnce sync.Once
+	registry     *PromptRegistry
+	registryErr  error
+
+	plainMu      sync.Mutex
+	plainPrompts bool
+)
+
+// SetPlainPrompts makes RenderPrompt leave out what depends on the current
+// project, the learned commit style and the content language, so results
+// can be compared across repositories, e.g. by evals
+func SetPlainPrompts(plain bool) {
+	plainMu.Lock()
+	defer plainMu.Unlock()
+	plainPrompts = plain
+}
+
+// LoadPrompts builds the registry from the embedded defaults,
+// the global configuration and the current project's overrides.
+func LoadPrompts() (*PromptRegistry, error) {
+	r := &PromptRegistry{layers: make(map[string][]Prompt)}
+
+	defaults, err := parsePrompts(defaultPrompts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse default prompts: %w", err)
+	}
+	for _, p := range defaults {
+		r.add(p, PromptSourceDefault)
+	}
+
+	// Global overrides: AI config first, then `yolo prompt set`
+	if aiConfig, err := LoadConfig(); err == nil {
+		r.addLegacy(aiConfig.Prompts)
+	}
+	if clientConfig, err := config.LoadClientConfig(); err == nil {
+		r.addMap(clientConfig.Prompts)
+	}
+
+	data, err := os.ReadFile(ProjectPromptsPath)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read project prompts: %w", err)
+	}
+	if len(data) > 0 {
+		overrides, err := parsePrompts(data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", ProjectPromptsPath, err)
+		}
+		for _, p := range overrides {
+			r.add(p, PromptSourceProject)
+		}
+	}
+
+	return r, nil
+}
+
+// RenderPrompt renders a prompt from the process-wide registry
+func RenderPrompt(name string, data PromptData) (string, error) {
+	registryOnce.Do(func() {
+		registry, registryErr = LoadPrompts()
+	})
+	if registryErr != nil {
+		return "", registryErr
+	}
+
+	text, err := registry.Render(name, data)
+	if err != nil {
+		return "", err
+	}
+	return registry.withInstructions(name, text)
+}
+
+// Effective returns the template of a prompt as it will be sent: its
+// effective definition, followed by the instructions RenderPrompt appends
+// for the commit style and the content language
+func (r *PromptRegistry) Effective(name string) (string, error) {
+	p, ok := r.Get(name)
+	if !ok {
+		return "", fmt.Errorf("unknown prompt: %s", name)
+	}
+	return r.withInstructions(name, p.Template)
+}
+
+// withInstructions appends to a prompt the instructions for the commit style
+// learned from the repository and for the content language, unless prompts
+// are plain
+func (r *PromptRegistry) withInstructions(name, text string) (string, error) {
+	plainMu.Lock()
+	plain := plainPrompts
+	plainMu.Unlock()
+	if plain {
+		return text, nil
+	}
+
+	// Commit prompts follow the conventions learned from the repository's history
+	if style := CommitStyle(); style != nil && r.IsCommitStyle(name) {
+		instruction, err := r.Render("commit.style", style)
+		if err != nil {
+			return "", err
+		}
+		text += "\n\n" + instruction
+	}
+
+	if !r.IsContent(name) {
+		return text, nil
+	}
+
+	// Content prompts are written in English but ask for the project's language
+	if lang := ContentLanguage(); !isEnglish(lang) {
+		instruction, err := r.Render("language", PromptData{"Language": LanguageName(lang)})
+		if err != nil {
+			return "", err
+		}
+		text += "\n\n" + instruction
+	}
+	return text, nil
+}
+
+// Get returns the effective definition of a prompt
+func (r *PromptRegistry) Get(name string) (Prompt, bool) {
+	layers := r.layers[name]
+	if len(layers) == 0 {
+		return Prompt{}, false
+	}
+	return layers[len(layers)-1], true
+}
+
+// IsContent reports whether a prompt generates project content.
+// Overrides inherit the flag from the definition they replace.
+func (r *PromptRegistry) IsContent(name string) bool {
+	for _, p := range r.layers[name] {
+		if p.Content {
+			return true
+		}
+	}
+	return false
+}
+
+// IsCommitStyle reports whether a prompt writes commit messages.
+// Overrides inherit the flag from the definition they replace.
+func (r *PromptRegistry) IsCommitStyle(name string) bool {
+	for _, p := range r.layers[name] {
+		if p.CommitStyle {
+			return true
+		}
+	}
+	return false
+}
+
+// Layers returns every definition of a prompt, lowest precedence first
+func (r *PromptRegistry) Layers(name string) []Prompt {
+	return r.layers[name]
+}
+
+// Names returns all known prompt names in sorted order
+func (r *PromptRegistry) Names() []string {
+	names := make([]string, 0, len(r.layers))
+	for name := range r.layers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Render executes the effective template of a prompt with the given data
+func (r *PromptRegistry) Render(name string, data PromptData) (string, error) {
+	p, ok := r.Get(name)
+	if !ok {
+		return "", fmt.Errorf("unknown prompt: %s", name)
+	}
+
+	tmpl, err := ParsePromptTemplate(p)
+	if err != nil {
+		return "", err
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("failed to render %s prompt %q: %w", p.Source, name, err)
+	}
+
+	return buf.String(), nil
+}
+
+// ParsePromptTemplate parses a prompt, failing on references to missing values
+func ParsePromptTemplate(p Prompt) (*template.Template, error) {
+	tmpl, err := template.New(p.Name).Option("missingkey=error").Parse(p.Template)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s prompt %q: %w", p.Source, p.Name, err)
+	}
+	return tmpl, nil
+}
+
+func (r *PromptRegistry) add(p Prompt, source string) {
+	if p.Name == "" || p.Template == "" {
+		return
+	}
+
+	// Overrides may omit the description, keep the one they replace
+	if p.Description == "" {
+		if prev, ok := r.Get(p.Name); ok {
+			p.Description = prev.Description
+		}
+	}
+
+	p.Source = source
+	r.layers[p.Name] = append(r.layers[p.Name], p)
+}
+
+func (r *PromptRegistry) addMap(prompts map[string]string) {
+	names := make([]string, 0, len(prompts))
+	for name := range prompts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		r.add(Prompt{Name: name, Template: prompts[name]}, PromptSourceGlobal)
+	}
+}
+
+// legacyPrompts maps the prompts of the AI config, written for Sprintf, to
+// the registry's prompts and the fields their verbs stand for, in order
+var legacyPrompts = map[string]struct {
+	name   string
+	fields []string
+}{
+	"commit": {"commit", []string{"Diff"}},
+	"ask":    {"ask", []string{"Question"}},
+	"error":  {"error.analyze", []string{"Context", "Error"}},
+}
+
+// legacyVerb matches the Sprintf verbs of legacy prompts
+var legacyVerb = regexp.MustCompile(`%%|%[sv]`)
+
+// addLegacy adds the prompts of the AI config. Their %s and %v verbs become
+// the template fields of the prompt they override; prompts whose verbs do not
+// match are skipped, and those left as the old defaults are ignored.
+func (r *PromptRegistry) addLegacy(prompts map[string]string) {
+	converted := make(map[string]string, len(prompts))
+	for name, text := range prompts {
+		if text == legacyDefaultPrompts[name] {
+			continue
+		}
+		legacy, ok := legacyPrompts[name]
+		if !ok {
+			converted[name] = text
+			continue
+		}
+
+		verbs := 0
+		for _, verb := range legacyVerb.FindAllString(text, -1) {
+			if verb != "%%" {
+				verbs++
+			}
+		}
+		if verbs == 0 {
+			converted[legacy.name] = text
+			continue
+		}
+		if verbs != len(legacy.fields) {
+			logging.Warn("ignoring the AI config prompt, its format verbs do not match the prompt's values",
+				"name", name, "hint", "rewrite it with: yolo prompt set "+legacy.name)
+			continue
+		}
+
+		field := 0
+		converted[legacy.name] = legacyVerb.ReplaceAllStringFunc(text, func(verb string) string {
+			if verb == "%%" {
+				return "%"
+			}
+			field++
+			return "{{." + legacy.fields[field-1] + "}}"
+		})
+	}
+	r.addMap(converted)
+}
+
+func parsePrompts(data []byte) ([]Prompt, error) {
+	var file promptFile
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, err
+	}
+	return file.Prompts, nil
+}
@@ -0,0 +1,68 @@
+package ai
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestEffectivePromptHasInstructions(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(home, ".config"))
+
+	project := t.TempDir()
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(project); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(wd) })
+
+	files := map[string]string{
+		"yolo/settings/config.yml": "content_language: es\n",
+		"yolo/settings/prompts.yml": `prompts:
+  - name: commit.style
+    template: "Follow these conventions: {{.Conventions}}"
+`,
+	}
+	for name, content := range files {
+		if err := os.MkdirAll(filepath.Dir(name), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(name, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	SetCommitStyle("types: feat, fix", "")
+	t.Cleanup(func() { SetCommitStyle("", "") })
+
+	registry, err := LoadPrompts()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	commit, err := registry.Effective("commit")
+	if err != nil {
+		t.Fatal(err)
+	}
+	template, _ := registry.Get("commit")
+	if want := template.Template + "\n\nFollow these conventions: types: feat, fix"; commit != want {
+		t.Errorf("effective commit prompt = %q, want %q", commit, want)
+	}
+
+	item, err := registry.Effective("chat.item")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasSuffix(item, "Write all natural-language text in Spanish.\nKeep work item IDs, file paths, code, JSON field names and allowed values\nsuch as statuses or commit types exactly as they are.") {
+		t.Errorf("effective chat.item prompt does not ask for Spanish:\n%s", item)
+	}
+	if strings.Contains(item, "Follow these conventions") {
+		t.Errorf("effective chat.item prompt has the commit style:\n%s", item)
+	}
+}
@@ -0,0 +1,79 @@
+package ai
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/baudevs/yolo.baudevs.com/internal/logging"
+	"github.com/sashabaranov/go-openai"
+)
+
+// Provider is the subset of the OpenAI API used by YOLO.
+// *openai.Client satisfies it, so does every wrapper in this package.
+type Provider interface {
+	CreateChatCompletion(ctx context.Context, req openai.ChatCompletionRequest) (openai.ChatCompletionResponse, error)
+}
+
+// NewProvider returns the provider used for all AI requests.
+// It is the offline mock provider when MockEnabled reports so, and OpenAI otherwise.
+// Every request made through it is redacted, audited and recorded in the usage ledger.
+func NewProvider(apiKey string) Provider {
+	if !MockEnabled() {
+		return &meteredProvider{next: &redactingProvider{next: openai.NewClient(apiKey)}}
+	}
+
+	mock, err := newConfiguredMockProvider()
+	if err != nil {
+		logging.Warn("using built-in mock fixtures only", "error", err)
+		defaults, _ := parseMockFixtures(defaultMockFixtures)
+		mock, _ = NewMockProvider(defaults...)
+	}
+
+	return &meteredProvider{next: &redactingProvider{next: mock}, mock: true}
+}
+
+// meteredProvider records token usage for each completed request. Requests
+// answered by the mock provider cost nothing and stay out of the ledger.
+type meteredProvider struct {
+	next Provider
+	mock bool
+}
+
+func (p *meteredProvider) CreateChatCompletion(ctx context.Context, req openai.ChatCompletionRequest) (openai.ChatCompletionResponse, error) {
+	start := time.Now()
+	resp, err := p.next.CreateChatCompletion(ctx, req)
+	if err != nil {
+		logging.Warn("AI request failed", "model", req.Model, "duration", time.Since(start), "error", err)
+		return resp, err
+	}
+
+	model := resp.Model
+	if model == "" {
+		model = req.Model
+	}
+	if !p.mock {
+		RecordUsage(model, resp.Usage)
+	}
+
+	logging.Info("AI request", "model", model, "duration", time.Since(start),
+		"prompt_tokens", resp.Usage.PromptTokens, "completion_tokens", resp.Usage.CompletionTokens)
+	if len(resp.Choices) > 0 {
+		logging.Debug("AI response", "model", model, logging.Response(responseText(resp.Choices[0].Message)))
+	}
+
+	return resp, nil
+}
+
+// responseText is what the AI answered: the content, or the arguments of the
+// functions it called for structured output
+func responseText(msg openai.ChatCompletionMessage) string {
+	parts := []string{msg.Content}
+	if msg.FunctionCall != nil {
+		parts = append(parts, msg.FunctionCall.Arguments)
+	}
+	for _, call := range msg.ToolCalls {
+		parts = append(parts, call.Function.Arguments)
+	}
+	return strings.TrimSpace(strings.Join(parts, "\n"))
+}
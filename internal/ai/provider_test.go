@@ -0,0 +1,31 @@
+package ai
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sashabaranov/go-openai"
+)
+
+func TestMockUsageNotRecorded(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("YOLO_AI_PROVIDER", MockModel)
+
+	since := time.Now().Add(-time.Minute)
+	_, err := NewProvider("").CreateChatCompletion(context.Background(), openai.ChatCompletionRequest{
+		Model:    DefaultModel,
+		Messages: []openai.ChatCompletionMessage{{Role: openai.ChatMessageRoleUser, Content: "hello"}},
+	})
+	if err != nil {
+		t.Fatalf("CreateChatCompletion: %v", err)
+	}
+
+	records, err := LoadUsage(since)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 0 {
+		t.Errorf("mock requests were recorded in the usage ledger: %+v", records)
+	}
+}
@@ -0,0 +1,390 @@
+package ai
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/baudevs/yolo.baudevs.com/internal/config"
+	"gopkg.in/yaml.v3"
+)
+
+// ProjectRedactionPath is the per-project redaction settings file,
+// in the same format as `ai.redaction` in the global config
+var ProjectRedactionPath = filepath.Join("yolo", "settings", "redaction.yml")
+
+const (
+	// entropyRule names the high-entropy string detector
+	entropyRule = "high-entropy"
+	// minEntropyLength and minEntropy tune the high-entropy detector:
+	// random tokens score around 4.5 to 6 bits per character, identifiers and hex hashes stay below
+	minEntropyLength = 24
+	minEntropy       = 4.2
+)
+
+// Redaction counts what a rule masked
+type Redaction struct {
+	Rule  string `json:"rule"`
+	Count int    `json:"count"`
+}
+
+// redactionRule masks every match of a pattern, or only one of its groups,
+// when it passes the rule's check, if any
+type redactionRule struct {
+	name  string
+	re    *regexp.Regexp
+	group int
+	check func(string) bool
+}
+
+// credentialKey matches the names of settings and variables holding secrets
+const credentialKey = `(?i)(?:password|passwd|secret|token|api[_-]?key|access[_-]?key)[A-Za-z0-9_]*["']?\s*[:=]\s*`
+
+// builtinRules detect common credentials
+var builtinRules = []redactionRule{
+	{name: "private-key", re: regexp.MustCompile(`-----BEGIN [A-Z ]*PRIVATE KEY-----[\s\S]*?-----END [A-Z ]*PRIVATE KEY-----`)},
+	{name: "openai-key", re: regexp.MustCompile(`\bsk-(?:proj-)?[A-Za-z0-9_-]{20,}`)},
+	{name: "aws-access-key", re: regexp.MustCompile(`\b(?:AKIA|ASIA)[0-9A-Z]{16}\b`)},
+	{name: "github-token", re: regexp.MustCompile(`\b(?:gh[pousr]_[A-Za-z0-9]{36,}|github_pat_[A-Za-z0-9_]{22,})`)},
+	{name: "slack-token", re: regexp.MustCompile(`\bxox[abeprs]-[A-Za-z0-9-]{10,}`)},
+	{name: "stripe-key", re: regexp.MustCompile(`\b[rs]k_(?:live|test)_[A-Za-z0-9]{16,}`)},
+	{name: "google-api-key", re: regexp.MustCompile(`\bAIza[0-9A-Za-z_-]{35}`)},
+	{name: "jwt", re: regexp.MustCompile(`\beyJ[A-Za-z0-9_-]{8,}\.eyJ[A-Za-z0-9_-]{8,}\.[A-Za-z0-9_-]{8,}`)},
+	// Quoted values are literals; unquoted ones may be identifiers or
+	// expressions in code, e.g. "apiKey: cfg.APIKey", and only count when
+	// they look generated
+	{name: "credential", re: regexp.MustCompile(credentialKey + `["'` + "`" + `]([^\s"'` + "`" + `]{8,})["'` + "`" + `]`), group: 1},
+	{name: "credential", re: regexp.MustCompile(credentialKey + `([A-Za-z0-9_+/=-]{8,})(?:[\s,;]|$)`), group: 1, check: looksGenerated},
+	{name: "url-credentials", re: regexp.MustCompile(`[a-z][a-z0-9+.-]*://[^\s:/@]+:([^\s@/]+)@`), group: 1},
+}
+
+// defaultExcludePaths are files whose content is never sent to the AI
+var defaultExcludePaths = []string{
+	".env", ".env.*", "*.pem", "*.key", "*.p12", "*.pfx", "*.keystore",
+	"id_rsa", "id_dsa", "id_ecdsa", "id_ed25519", ".npmrc", ".pypirc", ".netrc",
+}
+
+var entropyCandidate = regexp.MustCompile(`[A-Za-z0-9+/=_-]{24,}`)
+
+// Redactor masks secrets in text sent to the AI
+type Redactor struct {
+	rules    []redactionRule
+	excludes []string
+	disabled bool
+}
+
+var (
+	redactorOnce sync.Once
+	redactor     *Redactor
+	redactorErr  error
+)
+
+// NewRedactor creates a redactor with the built-in detectors,
+// the given extra patterns and the default plus given path excludes
+func NewRedactor(patterns, excludePaths []string) (*Redactor, error) {
+	r := &Redactor{
+		rules:    append([]redactionRule(nil), builtinRules...),
+		excludes: append(append([]string(nil), defaultExcludePaths...), excludePaths...),
+	}
+
+	for _, pattern := range patterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid redaction pattern %q: %w", pattern, err)
+		}
+		r.rules = append(r.rules, redactionRule{name: "custom", re: re})
+	}
+
+	return r, nil
+}
+
+// LoadRedactor builds the redactor from the global config and the project settings
+func LoadRedactor() (*Redactor, error) {
+	var settings config.RedactionConfig
+	if cfg, err := config.LoadConfig(); err == nil {
+		settings = cfg.AI.Redaction
+	}
+
+	data, err := os.ReadFile(ProjectRedactionPath)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read redaction settings: %w", err)
+	}
+	if len(data) > 0 {
+		var project config.RedactionConfig
+		if err := yaml.Unmarshal(data, &project); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", ProjectRedactionPath, err)
+		}
+		settings.Disabled = settings.Disabled || project.Disabled
+		settings.Patterns = append(settings.Patterns, project.Patterns...)
+		settings.ExcludePaths = append(settings.ExcludePaths, project.ExcludePaths...)
+	}
+
+	r, err := NewRedactor(settings.Patterns, settings.ExcludePaths)
+	if err != nil {
+		return nil, err
+	}
+	r.disabled = settings.Disabled
+	return r, nil
+}
+
+// DefaultRedactor returns the process-wide redactor
+func DefaultRedactor() (*Redactor, error) {
+	redactorOnce.Do(func() {
+		redactor, redactorErr = LoadRedactor()
+	})
+	return redactor, redactorErr
+}
+
+// Redact masks secrets in text and reports what was masked
+func (r *Redactor) Redact(text string) (string, []Redaction) {
+	if r.disabled {
+		return text, nil
+	}
+
+	counts := make(map[string]int)
+	for _, rule := range r.rules {
+		text = maskMatches(text, rule, counts)
+	}
+	text = entropyCandidate.ReplaceAllStringFunc(text, func(candidate string) string {
+		if !isHighEntropy(candidate) {
+			return candidate
+		}
+		counts[entropyRule]++
+		return mask(entropyRule)
+	})
+
+	return text, sortedRedactions(counts)
+}
+
+// ExcludesPath reports whether a file must never be sent to the AI
+func (r *Redactor) ExcludesPath(path string) bool {
+	path = filepath.ToSlash(path)
+	base := filepath.Base(path)
+	for _, pattern := range r.excludes {
+		if strings.HasSuffix(pattern, "/") {
+			if strings.HasPrefix(path, pattern) || strings.Contains(path, "/"+pattern) {
+				return true
+			}
+			continue
+		}
+		if ok, _ := filepath.Match(pattern, path); ok {
+			return true
+		}
+		if ok, _ := filepath.Match(pattern, base); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// RedactDiff drops the content of excluded files from a git diff,
+// keeping their headers so the AI still knows they changed.
+// It returns the diff and the excluded files.
+func (r *Redactor) RedactDiff(diff string) (string, []string) {
+	var sb strings.Builder
+	var excluded []string
+	skipping := false
+
+	for _, line := range strings.SplitAfter(diff, "\n") {
+		if strings.HasPrefix(line, "diff --git ") {
+			skipping = false
+			if path := diffPath(line); path != "" && r.ExcludesPath(path) {
+				skipping = true
+				excluded = append(excluded, path)
+				sb.WriteString(line)
+				sb.WriteString(mask("excluded-path") + " content of " + path + " is not sent to the AI\n")
+				continue
+			}
+		}
+		if !skipping {
+			sb.WriteString(line)
+		}
+	}
+
+	return sb.String(), excluded
+}
+
+// diffPath extracts the new path from a "diff --git a/x b/x" header
+func diffPath(header string) string {
+	header = strings.TrimSpace(header)
+	if i := strings.LastIndex(header, " b/"); i != -1 {
+		return header[i+3:]
+	}
+	return ""
+}
+
+func maskMatches(text string, rule redactionRule, counts map[string]int) string {
+	matches := rule.re.FindAllStringSubmatchIndex(text, -1)
+	if len(matches) == 0 {
+		return text
+	}
+
+	var sb strings.Builder
+	last := 0
+	for _, m := range matches {
+		start, end := m[0], m[1]
+		if rule.group > 0 {
+			start, end = m[2*rule.group], m[2*rule.group+1]
+			if start < 0 {
+				continue
+			}
+		}
+		if strings.HasPrefix(text[start:], "[REDACTED:") {
+			continue // Already masked by an earlier rule
+		}
+		if rule.check != nil && !rule.check(text[start:end]) {
+			continue
+		}
+		sb.WriteString(text[last:start])
+		sb.WriteString(mask(rule.name))
+		last = end
+		counts[rule.name]++
+	}
+	sb.WriteString(text[last:])
+
+	return sb.String()
+}
+
+func mask(rule string) string {
+	return "[REDACTED:" + rule + "]"
+}
+
+// looksGenerated reports whether an unquoted value looks like a secret rather
+// than a name: it mixes letters and digits, or is a high-entropy token
+func looksGenerated(value string) bool {
+	hasDigit := strings.ContainsAny(value, "0123456789")
+	hasLetter := strings.IndexFunc(value, func(c rune) bool {
+		return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+	}) != -1
+	return (hasDigit && hasLetter) || isHighEntropy(value)
+}
+
+// isHighEntropy reports whether a token looks randomly generated
+func isHighEntropy(token string) bool {
+	if len(token) < minEntropyLength {
+		return false
+	}
+
+	hasDigit, hasLetter, onlyHex := false, false, true
+	freq := make(map[rune]float64)
+	for _, c := range token {
+		freq[c]++
+		switch {
+		case c >= '0' && c <= '9':
+			hasDigit = true
+		case (c >= 'a' && c <= 'f') || (c >= 'A' && c <= 'F'):
+			hasLetter = true
+		case (c >= 'g' && c <= 'z') || (c >= 'G' && c <= 'Z'):
+			hasLetter = true
+			onlyHex = false
+		default:
+			onlyHex = false
+		}
+	}
+	// Hex digests such as commit hashes are common in diffs and not secrets,
+	// neither are long names in code
+	if !hasDigit || !hasLetter || onlyHex || isIdentifier(token) {
+		return false
+	}
+
+	entropy := 0.0
+	n := float64(len(token))
+	for _, count := range freq {
+		p := count / n
+		entropy -= p * math.Log2(p)
+	}
+	return entropy >= minEntropy
+}
+
+// isIdentifier reports whether a token is shaped like a name in code, e.g.
+// TestParse_emptyInput2 or MAX_RETRY_COUNT: mostly camel or snake case words
+// with few numbers, where random tokens break into short fragments
+func isIdentifier(token string) bool {
+	if strings.ContainsAny(token, "+/=") {
+		return false
+	}
+
+	words, fragments, numbers := 0, 0, 0
+	for _, w := range identifierWords(token) {
+		switch {
+		case w[0] >= '0' && w[0] <= '9':
+			numbers++
+		case len(w) >= 3 && strings.ContainsAny(w, "aeiouyAEIOUY"):
+			words++
+		default:
+			fragments++
+		}
+	}
+	return numbers <= 2 && words >= 3 && fragments < words
+}
+
+// Character classes of identifierWords
+const (
+	charSeparator = iota
+	charLower
+	charUpper
+	charDigit
+)
+
+// identifierWords splits a token at _ and - and at case and digit
+// boundaries, e.g. "parseJSONFile2" into parse, JSON, File and 2
+func identifierWords(token string) []string {
+	class := func(c byte) int {
+		switch {
+		case c >= 'a' && c <= 'z':
+			return charLower
+		case c >= 'A' && c <= 'Z':
+			return charUpper
+		case c >= '0' && c <= '9':
+			return charDigit
+		}
+		return charSeparator
+	}
+
+	var words []string
+	start := 0
+	for i := 0; i < len(token); i++ {
+		c := class(token[i])
+		if c == charSeparator {
+			if i > start {
+				words = append(words, token[start:i])
+			}
+			start = i + 1
+			continue
+		}
+		if i == start {
+			continue
+		}
+
+		prev := class(token[i-1])
+		split := c != prev && !(prev == charUpper && c == charLower)
+		// In "JSONFile" the last capital starts the next word
+		if prev == charUpper && c == charUpper && i+1 < len(token) && class(token[i+1]) == charLower {
+			split = true
+		}
+		if split {
+			words = append(words, token[start:i])
+			start = i
+		}
+	}
+	if start < len(token) {
+		words = append(words, token[start:])
+	}
+	return words
+}
+
+func sortedRedactions(counts map[string]int) []Redaction {
+	var redactions []Redaction
+	for rule, count := range counts {
+		redactions = append(redactions, Redaction{Rule: rule, Count: count})
+	}
+	sort.Slice(redactions, func(i, j int) bool {
+		return redactions[i].Rule < redactions[j].Rule
+	})
+	return redactions
+}
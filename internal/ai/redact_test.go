@@ -0,0 +1,69 @@
+package ai
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRedactMasksSecrets(t *testing.T) {
+	r, err := NewRedactor(nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name, text, rule string
+	}{
+		{"openai key", `client := openai.NewClient("sk-proj-Xk29vLq8ZtR4mWn7Pb3cYd6F")`, "openai-key"},
+		{"quoted credential", `password: "hunter2hunter2"`, "credential"},
+		{"unquoted credential", `API_KEY=f8Kq2xVz9LmP4wRt`, "credential"},
+		{"random token", `const session = "q7Xv2LpK9zR4tWm8NbY3cF6hJd1Gs5Ae"`, entropyRule},
+		{"random token with underscores", `nonce = Zq8_xV2kLp9_mR4tWn7_bY3cQ6`, entropyRule},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			masked, _ := r.Redact(tt.text)
+			if !strings.Contains(masked, mask(tt.rule)) {
+				t.Errorf("Redact(%q) = %q, want it masked as %s", tt.text, masked, tt.rule)
+			}
+		})
+	}
+}
+
+func TestRedactKeepsIdentifiers(t *testing.T) {
+	r, err := NewRedactor(nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name, text string
+	}{
+		{"go test", `func TestGenerateStructuredJSONMode_withRepairAttempt2(t *testing.T) {`},
+		{"go constant", `const defaultHTTPClientTimeoutSeconds30 = 30`},
+		{"js camel case", `const user = await fetchUserProfileById2(userId)`},
+		{"js hook", `const { data } = useInfiniteQueryWithPagination_v5(options)`},
+		{"js react", `componentDidUpdatePrevProps2(prevProps) {`},
+		{"python test", `def test_parse_config_file_with_utf8_bom(tmp_path):`},
+		{"python constant", `MAX_RETRY_ATTEMPTS_FOR_UPLOAD_V2 = 5`},
+		{"python dunder", `def __init_subclass_hook_v2__(cls):`},
+		{"commit hash", `Commit 4f2a9c81d3e5b7a0c6f9e2d1b8a7c3e5f0d9b2a4: fix parser`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			masked, redactions := r.Redact(tt.text)
+			if masked != tt.text {
+				t.Errorf("Redact(%q) = %q, %v, want it unchanged", tt.text, masked, redactions)
+			}
+		})
+	}
+}
+
+func TestIdentifierWords(t *testing.T) {
+	got := identifierWords("parseJSONFile2_for-HTTPServer")
+	want := []string{"parse", "JSON", "File", "2", "for", "HTTP", "Server"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("identifierWords = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,236 @@
+package ai
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// JSONSchemaType represents a JSON Schema type
+type JSONSchemaType string
+
+const (
+	JSONSchemaTypeString  JSONSchemaType = "string"
+	JSONSchemaTypeNumber  JSONSchemaType = "number"
+	JSONSchemaTypeInteger JSONSchemaType = "integer"
+	JSONSchemaTypeObject  JSONSchemaType = "object"
+	JSONSchemaTypeArray   JSONSchemaType = "array"
+	JSONSchemaTypeBoolean JSONSchemaType = "boolean"
+	JSONSchemaTypeNull    JSONSchemaType = "null"
+)
+
+// JSONSchemaDefinition represents a JSON Schema definition
+type JSONSchemaDefinition struct {
+	Type        JSONSchemaType                  `json:"type,omitempty"`
+	Description string                          `json:"description,omitempty"`
+	Properties  map[string]JSONSchemaDefinition `json:"properties,omitempty"`
+	Items       *JSONSchemaDefinition           `json:"items,omitempty"`
+	Required    []string                        `json:"required,omitempty"`
+	Enum        []string                        `json:"enum,omitempty"`
+	Ref         string                          `json:"$ref,omitempty"`
+	OneOf       []JSONSchemaDefinition          `json:"oneOf,omitempty"`
+	AnyOf       []JSONSchemaDefinition          `json:"anyOf,omitempty"`
+	AllOf       []JSONSchemaDefinition          `json:"allOf,omitempty"`
+	Not         *JSONSchemaDefinition           `json:"not,omitempty"`
+	Definitions map[string]JSONSchemaDefinition `json:"definitions,omitempty"`
+}
+
+// SchemaFor derives a JSON Schema from a Go value's type.
+//
+// Field names come from `json` tags; fields without `omitempty` are required.
+// A `description` tag documents a field and an `enum` tag holds a
+// comma-separated list of allowed string values.
+func SchemaFor(v interface{}) JSONSchemaDefinition {
+	return schemaForType(reflect.TypeOf(v))
+}
+
+func schemaForType(t reflect.Type) JSONSchemaDefinition {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return JSONSchemaDefinition{Type: JSONSchemaTypeString}
+	case reflect.Bool:
+		return JSONSchemaDefinition{Type: JSONSchemaTypeBoolean}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return JSONSchemaDefinition{Type: JSONSchemaTypeInteger}
+	case reflect.Float32, reflect.Float64:
+		return JSONSchemaDefinition{Type: JSONSchemaTypeNumber}
+	case reflect.Slice, reflect.Array:
+		items := schemaForType(t.Elem())
+		return JSONSchemaDefinition{Type: JSONSchemaTypeArray, Items: &items}
+	case reflect.Map:
+		return JSONSchemaDefinition{Type: JSONSchemaTypeObject}
+	case reflect.Struct:
+		return schemaForStruct(t)
+	default:
+		return JSONSchemaDefinition{}
+	}
+}
+
+func schemaForStruct(t reflect.Type) JSONSchemaDefinition {
+	def := JSONSchemaDefinition{
+		Type:       JSONSchemaTypeObject,
+		Properties: make(map[string]JSONSchemaDefinition),
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name, omitEmpty := jsonFieldName(field)
+		if name == "-" {
+			continue
+		}
+
+		prop := schemaForType(field.Type)
+		prop.Description = field.Tag.Get("description")
+		if enum := field.Tag.Get("enum"); enum != "" {
+			prop.Enum = strings.Split(enum, ",")
+		}
+
+		def.Properties[name] = prop
+		if !omitEmpty {
+			def.Required = append(def.Required, name)
+		}
+	}
+
+	return def
+}
+
+// jsonFieldName returns the JSON name of a struct field and whether it is omitempty
+func jsonFieldName(field reflect.StructField) (string, bool) {
+	tag := field.Tag.Get("json")
+	if tag == "" {
+		return field.Name, false
+	}
+
+	parts := strings.Split(tag, ",")
+	name := parts[0]
+	if name == "" {
+		name = field.Name
+	}
+
+	omitEmpty := false
+	for _, opt := range parts[1:] {
+		if opt == "omitempty" {
+			omitEmpty = true
+		}
+	}
+
+	return name, omitEmpty
+}
+
+// Validate checks a decoded JSON value against the schema and returns
+// every violation found, each prefixed with its JSON path.
+func (d JSONSchemaDefinition) Validate(value interface{}) []string {
+	var errs []string
+	d.validate("$", value, &errs)
+	return errs
+}
+
+func (d JSONSchemaDefinition) validate(path string, value interface{}, errs *[]string) {
+	if value == nil {
+		if d.Type != "" && d.Type != JSONSchemaTypeNull {
+			*errs = append(*errs, fmt.Sprintf("%s: expected %s, got null", path, d.Type))
+		}
+		return
+	}
+
+	switch d.Type {
+	case JSONSchemaTypeString:
+		s, ok := value.(string)
+		if !ok {
+			*errs = append(*errs, fmt.Sprintf("%s: expected string, got %s", path, jsonTypeName(value)))
+			return
+		}
+		if len(d.Enum) > 0 && !containsString(d.Enum, s) {
+			*errs = append(*errs, fmt.Sprintf("%s: %q is not one of [%s]", path, s, strings.Join(d.Enum, ", ")))
+		}
+
+	case JSONSchemaTypeBoolean:
+		if _, ok := value.(bool); !ok {
+			*errs = append(*errs, fmt.Sprintf("%s: expected boolean, got %s", path, jsonTypeName(value)))
+		}
+
+	case JSONSchemaTypeNumber:
+		if _, ok := value.(float64); !ok {
+			*errs = append(*errs, fmt.Sprintf("%s: expected number, got %s", path, jsonTypeName(value)))
+		}
+
+	case JSONSchemaTypeInteger:
+		n, ok := value.(float64)
+		if !ok || n != float64(int64(n)) {
+			*errs = append(*errs, fmt.Sprintf("%s: expected integer, got %s", path, jsonTypeName(value)))
+		}
+
+	case JSONSchemaTypeArray:
+		items, ok := value.([]interface{})
+		if !ok {
+			*errs = append(*errs, fmt.Sprintf("%s: expected array, got %s", path, jsonTypeName(value)))
+			return
+		}
+		if d.Items != nil {
+			for i, item := range items {
+				d.Items.validate(fmt.Sprintf("%s[%d]", path, i), item, errs)
+			}
+		}
+
+	case JSONSchemaTypeObject:
+		obj, ok := value.(map[string]interface{})
+		if !ok {
+			*errs = append(*errs, fmt.Sprintf("%s: expected object, got %s", path, jsonTypeName(value)))
+			return
+		}
+		for _, name := range d.Required {
+			if _, ok := obj[name]; !ok {
+				*errs = append(*errs, fmt.Sprintf("%s: missing required field %q", path, name))
+			}
+		}
+
+		// Visit properties in a stable order so error messages are reproducible
+		names := make([]string, 0, len(d.Properties))
+		for name := range d.Properties {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			if v, ok := obj[name]; ok {
+				d.Properties[name].validate(path+"."+name, v, errs)
+			}
+		}
+	}
+}
+
+// jsonTypeName returns the JSON type name of a decoded value
+func jsonTypeName(value interface{}) string {
+	switch value.(type) {
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case float64:
+		return "number"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
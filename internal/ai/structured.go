@@ -0,0 +1,207 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/baudevs/yolo.baudevs.com/internal/config"
+	"github.com/baudevs/yolo.baudevs.com/internal/logging"
+	"github.com/sashabaranov/go-openai"
+)
+
+// maxRepairAttempts is how many times an invalid response is sent back for correction
+const maxRepairAttempts = 2
+
+// StructuredMode is how a structured answer is requested from the model
+type StructuredMode string
+
+const (
+	// StructuredFunctions forces a call of a function taking the schema
+	StructuredFunctions StructuredMode = "functions"
+	// StructuredJSON turns on JSON mode and gives the schema in a system prompt
+	StructuredJSON StructuredMode = "json"
+	// StructuredPrompt only gives the schema in a system prompt, for models
+	// with neither function calling nor JSON mode
+	StructuredPrompt StructuredMode = "prompt"
+)
+
+// structuredModes are the modes of models without function calling, matched
+// by longest prefix; other models use StructuredFunctions
+var structuredModes = map[string]StructuredMode{
+	"o1-mini":            StructuredPrompt,
+	"o1-preview":         StructuredPrompt,
+	"gpt-4-vision":       StructuredPrompt,
+	"gpt-4-0314":         StructuredPrompt,
+	"gpt-4-32k-0314":     StructuredPrompt,
+	"gpt-3.5-turbo-0301": StructuredPrompt,
+}
+
+// StructuredRequest describes a request whose answer must decode into a Go type
+type StructuredRequest struct {
+	Model       string
+	Messages    []openai.ChatCompletionMessage
+	Name        string // Function name presented to the model
+	Description string // What the function produces
+	Temperature float32
+	Mode        StructuredMode // Default: StructuredModeFor(Model)
+}
+
+// StructuredModeFor returns how structured answers are requested from a
+// model: as set by ai.structured_output in the config, or else by what the
+// model supports
+func StructuredModeFor(model string) StructuredMode {
+	if cfg, err := config.LoadConfig(); err == nil {
+		switch mode := StructuredMode(strings.ToLower(cfg.AI.StructuredOutput)); mode {
+		case StructuredFunctions, StructuredJSON, StructuredPrompt:
+			return mode
+		case "", "auto":
+		default:
+			logging.Warn("unknown ai.structured_output, choosing by model", "value", cfg.AI.StructuredOutput)
+		}
+	}
+
+	best := ""
+	for prefix := range structuredModes {
+		if strings.HasPrefix(model, prefix) && len(prefix) > len(best) {
+			best = prefix
+		}
+	}
+	if best == "" {
+		return StructuredFunctions
+	}
+	return structuredModes[best]
+}
+
+// GenerateStructured asks the model for a value of type T.
+//
+// The JSON Schema for T is derived with SchemaFor and offered as a forced
+// function call or, for models without function calling, given in a system
+// prompt, with JSON mode when the model has it (see StructuredModeFor). The
+// result is validated against the schema and, when it does not conform, the
+// validation errors are sent back so the model can repair its answer.
+func GenerateStructured[T any](ctx context.Context, p Provider, req StructuredRequest) (T, error) {
+	var result T
+
+	schema := SchemaFor(result)
+	mode := req.Mode
+	if mode == "" {
+		mode = StructuredModeFor(req.Model)
+	}
+
+	base := openai.ChatCompletionRequest{
+		Model:       req.Model,
+		Temperature: req.Temperature,
+	}
+	messages := append([]openai.ChatCompletionMessage{}, req.Messages...)
+	switch mode {
+	case StructuredFunctions:
+		base.Functions = []openai.FunctionDefinition{
+			{
+				Name:        req.Name,
+				Description: req.Description,
+				Parameters:  schema,
+			},
+		}
+		base.FunctionCall = &openai.FunctionCall{Name: req.Name}
+	case StructuredJSON, StructuredPrompt:
+		schemaJSON, err := json.MarshalIndent(schema, "", "  ")
+		if err != nil {
+			return result, fmt.Errorf("failed to marshal schema: %w", err)
+		}
+		instructions, err := RenderPrompt("structured.json", PromptData{
+			"Description": req.Description,
+			"Schema":      string(schemaJSON),
+		})
+		if err != nil {
+			return result, err
+		}
+		messages = append([]openai.ChatCompletionMessage{
+			{Role: openai.ChatMessageRoleSystem, Content: instructions},
+		}, messages...)
+		if mode == StructuredJSON {
+			base.ResponseFormat = &openai.ChatCompletionResponseFormat{
+				Type: openai.ChatCompletionResponseFormatTypeJSONObject,
+			}
+		}
+	default:
+		return result, fmt.Errorf("unknown structured output mode %q", mode)
+	}
+
+	var problems []string
+	for attempt := 0; attempt <= maxRepairAttempts; attempt++ {
+		request := base
+		request.Messages = messages
+		resp, err := p.CreateChatCompletion(ctx, request)
+		if err != nil {
+			return result, fmt.Errorf("failed to create chat completion: %w", err)
+		}
+
+		if len(resp.Choices) == 0 {
+			return result, fmt.Errorf("no response from AI")
+		}
+
+		// Models without function calling answer in plain content instead
+		msg := resp.Choices[0].Message
+		raw := msg.Content
+		if msg.FunctionCall != nil {
+			raw = msg.FunctionCall.Arguments
+		}
+
+		problems = checkStructured(schema, raw)
+		if len(problems) == 0 {
+			if err := json.Unmarshal([]byte(extractJSON(raw)), &result); err != nil {
+				return result, fmt.Errorf("failed to decode structured output: %w", err)
+			}
+			return result, nil
+		}
+
+		messages = append(messages,
+			openai.ChatCompletionMessage{
+				Role:    openai.ChatMessageRoleAssistant,
+				Content: raw,
+			},
+			openai.ChatCompletionMessage{
+				Role: openai.ChatMessageRoleUser,
+				Content: fmt.Sprintf(`Your previous response did not match the required JSON schema:
+- %s
+
+Respond again with only the corrected JSON object.`, strings.Join(problems, "\n- ")),
+			},
+		)
+	}
+
+	return result, fmt.Errorf("invalid structured output after %d attempts: %s",
+		maxRepairAttempts+1, strings.Join(problems, "; "))
+}
+
+// checkStructured parses raw model output and validates it against the schema
+func checkStructured(schema JSONSchemaDefinition, raw string) []string {
+	var value interface{}
+	if err := json.Unmarshal([]byte(extractJSON(raw)), &value); err != nil {
+		return []string{fmt.Sprintf("response is not valid JSON: %v", err)}
+	}
+	return schema.Validate(value)
+}
+
+// extractJSON strips markdown code fences and surrounding prose from a JSON answer
+func extractJSON(raw string) string {
+	s := strings.TrimSpace(raw)
+
+	if strings.HasPrefix(s, "```") {
+		s = strings.TrimPrefix(s, "```")
+		if i := strings.Index(s, "\n"); i != -1 {
+			s = s[i+1:] // Drop the language tag, e.g. ```json
+		}
+		s = strings.TrimSuffix(strings.TrimSpace(s), "```")
+	}
+
+	start := strings.IndexAny(s, "{[")
+	end := strings.LastIndexAny(s, "}]")
+	if start == -1 || end < start {
+		return strings.TrimSpace(s)
+	}
+
+	return s[start : end+1]
+}
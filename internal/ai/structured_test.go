@@ -0,0 +1,70 @@
+package ai
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sashabaranov/go-openai"
+)
+
+type testAnswer struct {
+	Title string `json:"title" description:"Short title"`
+	Score int    `json:"score" description:"Score from 1 to 5"`
+}
+
+func TestGenerateStructuredJSONMode(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	// The first answer misses a required field, the repaired one is complete
+	mock, err := NewMockProvider(
+		MockFixture{Match: `did not match the required JSON schema`, Response: `{"title": "Parser", "score": 4}`},
+		MockFixture{Match: `Rate this`, Response: "```json\n{\"title\": \"Parser\"}\n```"},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	answer, err := GenerateStructured[testAnswer](context.Background(), mock, StructuredRequest{
+		Model:       DefaultModel,
+		Messages:    []openai.ChatCompletionMessage{{Role: openai.ChatMessageRoleUser, Content: "Rate this change"}},
+		Name:        "rate",
+		Description: "Rate the change",
+		Mode:        StructuredJSON,
+	})
+	if err != nil {
+		t.Fatalf("GenerateStructured: %v", err)
+	}
+	if answer != (testAnswer{Title: "Parser", Score: 4}) {
+		t.Errorf("answer = %+v", answer)
+	}
+
+	requests := mock.Requests()
+	if len(requests) != 2 {
+		t.Fatalf("got %d requests, want 2 with one repair", len(requests))
+	}
+	for _, req := range requests {
+		if len(req.Functions) > 0 || req.FunctionCall != nil {
+			t.Error("JSON mode requests should not offer functions")
+		}
+		if !isJSONMode(req) {
+			t.Error("JSON mode requests should set the json_object response format")
+		}
+		if req.Messages[0].Role != openai.ChatMessageRoleSystem {
+			t.Error("JSON mode requests should start with the schema system prompt")
+		}
+	}
+}
+
+func TestStructuredModeFor(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	for model, want := range map[string]StructuredMode{
+		"gpt-4o":             StructuredFunctions,
+		"gpt-4-0314":         StructuredPrompt,
+		"o1-mini-2024-09-12": StructuredPrompt,
+	} {
+		if got := StructuredModeFor(model); got != want {
+			t.Errorf("StructuredModeFor(%q) = %q, want %q", model, got, want)
+		}
+	}
+}
@@ -0,0 +1,249 @@
+package ai
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/baudevs/yolo.baudevs.com/internal/logging"
+	"github.com/sashabaranov/go-openai"
+)
+
+const usageFileName = "usage.jsonl"
+
+// UsageRecord is a single entry in the usage ledger
+type UsageRecord struct {
+	Time             time.Time `json:"time"`
+	Command          string    `json:"command"`
+	Model            string    `json:"model"`
+	Project          string    `json:"project"`
+	PromptTokens     int       `json:"prompt_tokens"`
+	CompletionTokens int       `json:"completion_tokens"`
+	TotalTokens      int       `json:"total_tokens"`
+	Cost             float64   `json:"cost_usd"`
+}
+
+// UsageSummary aggregates usage records sharing the same key
+type UsageSummary struct {
+	Key              string  `json:"key"`
+	Requests         int     `json:"requests"`
+	PromptTokens     int     `json:"prompt_tokens"`
+	CompletionTokens int     `json:"completion_tokens"`
+	TotalTokens      int     `json:"total_tokens"`
+	Cost             float64 `json:"cost_usd"`
+}
+
+// modelPrice holds the USD price per 1K prompt and completion tokens
+type modelPrice struct {
+	prompt     float64
+	completion float64
+}
+
+// modelPrices is matched by longest prefix, so dated snapshots
+// (e.g. gpt-4-0613) resolve to their family price.
+var modelPrices = map[string]modelPrice{
+	"gpt-4o-mini":        {prompt: 0.00015, completion: 0.0006},
+	"gpt-4o":             {prompt: 0.005, completion: 0.015},
+	"gpt-4-turbo":        {prompt: 0.01, completion: 0.03},
+	"gpt-4-1106":         {prompt: 0.01, completion: 0.03},
+	"gpt-4-0125":         {prompt: 0.01, completion: 0.03},
+	"gpt-4-32k":          {prompt: 0.06, completion: 0.12},
+	"gpt-4":              {prompt: 0.03, completion: 0.06},
+	"gpt-3.5-turbo":      {prompt: 0.0005, completion: 0.0015},
+	"text-embedding-3":   {prompt: 0.00002},
+	"text-embedding-ada": {prompt: 0.0001},
+}
+
+var (
+	usageMu      sync.Mutex
+	usageCommand = "unknown"
+)
+
+// SetCommand tags subsequent usage records with the given command name
+func SetCommand(name string) {
+	usageMu.Lock()
+	defer usageMu.Unlock()
+	usageCommand = name
+}
+
+// EstimateCost returns the estimated USD cost of a request.
+// Unknown models are reported as free rather than guessed.
+func EstimateCost(model string, usage openai.Usage) float64 {
+	var best string
+	for prefix := range modelPrices {
+		if strings.HasPrefix(model, prefix) && len(prefix) > len(best) {
+			best = prefix
+		}
+	}
+	if best == "" {
+		return 0
+	}
+
+	price := modelPrices[best]
+	return float64(usage.PromptTokens)/1000*price.prompt +
+		float64(usage.CompletionTokens)/1000*price.completion
+}
+
+// RecordUsage appends the usage of a completed request to the ledger.
+// Failures are reported on stderr but never interrupt the command.
+func RecordUsage(model string, usage openai.Usage) {
+	usageMu.Lock()
+	defer usageMu.Unlock()
+
+	record := UsageRecord{
+		Time:             time.Now().UTC(),
+		Command:          usageCommand,
+		Model:            model,
+		Project:          currentProject(),
+		PromptTokens:     usage.PromptTokens,
+		CompletionTokens: usage.CompletionTokens,
+		TotalTokens:      usage.TotalTokens,
+		Cost:             EstimateCost(model, usage),
+	}
+
+	if err := appendUsage(record); err != nil {
+		logging.Warn("failed to record AI usage", "error", err)
+	}
+}
+
+// LoadUsage reads all ledger records created at or after since
+func LoadUsage(since time.Time) ([]UsageRecord, error) {
+	path, err := usagePath()
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to open usage ledger: %w", err)
+	}
+	defer f.Close()
+
+	var records []UsageRecord
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		var record UsageRecord
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			// Skip partially written lines instead of failing the whole report
+			continue
+		}
+		if record.Time.Before(since) {
+			continue
+		}
+		records = append(records, record)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read usage ledger: %w", err)
+	}
+
+	return records, nil
+}
+
+// SummarizeUsage groups records by "command", "model" or "project",
+// ordered by descending cost.
+func SummarizeUsage(records []UsageRecord, by string) ([]UsageSummary, error) {
+	keyOf := map[string]func(UsageRecord) string{
+		"command": func(r UsageRecord) string { return r.Command },
+		"model":   func(r UsageRecord) string { return r.Model },
+		"project": func(r UsageRecord) string { return r.Project },
+	}[by]
+	if keyOf == nil {
+		return nil, fmt.Errorf("invalid grouping %q (use command, model or project)", by)
+	}
+
+	byKey := make(map[string]*UsageSummary)
+	for _, r := range records {
+		key := keyOf(r)
+		s, ok := byKey[key]
+		if !ok {
+			s = &UsageSummary{Key: key}
+			byKey[key] = s
+		}
+		s.Requests++
+		s.PromptTokens += r.PromptTokens
+		s.CompletionTokens += r.CompletionTokens
+		s.TotalTokens += r.TotalTokens
+		s.Cost += r.Cost
+	}
+
+	summaries := make([]UsageSummary, 0, len(byKey))
+	for _, s := range byKey {
+		summaries = append(summaries, *s)
+	}
+	sort.Slice(summaries, func(i, j int) bool {
+		if summaries[i].Cost != summaries[j].Cost {
+			return summaries[i].Cost > summaries[j].Cost
+		}
+		return summaries[i].Key < summaries[j].Key
+	})
+
+	return summaries, nil
+}
+
+// appendUsage writes a single record as one JSON line
+func appendUsage(record UsageRecord) error {
+	path, err := usagePath()
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create usage directory: %w", err)
+	}
+
+	data, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to marshal usage record: %w", err)
+	}
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open usage ledger: %w", err)
+	}
+	defer f.Close()
+
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write usage record: %w", err)
+	}
+
+	return nil
+}
+
+// usagePath returns the path to the usage ledger
+func usagePath() (string, error) {
+	configDir, err := getConfigDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(configDir, usageFileName), nil
+}
+
+// currentProject names the project by its git root, falling back to the working directory
+func currentProject() string {
+	out, err := exec.Command("git", "rev-parse", "--show-toplevel").Output()
+	if err == nil {
+		return filepath.Base(strings.TrimSpace(string(out)))
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		return "unknown"
+	}
+	return filepath.Base(wd)
+}
@@ -0,0 +1,169 @@
+package chat
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/sashabaranov/go-openai"
+)
+
+// maxHistoryMessages bounds how much of the conversation is sent back to the AI
+const maxHistoryMessages = 20
+
+// Message is one turn of a chat session
+type Message struct {
+	Role    string    `json:"role"`
+	Content string    `json:"content"`
+	Time    time.Time `json:"time"`
+}
+
+// Session is a conversation persisted in the project
+type Session struct {
+	ID       string    `json:"id"`
+	Created  time.Time `json:"created"`
+	Updated  time.Time `json:"updated"`
+	Messages []Message `json:"messages"`
+	Items    []string  `json:"items,omitempty"` // IDs of work items created from the session
+}
+
+// SessionDir returns where the sessions of a project are stored
+func SessionDir(root string) string {
+	return filepath.Join(root, ".yolo", "chat")
+}
+
+// NewSession starts an empty session identified by its start time
+func NewSession() *Session {
+	now := time.Now()
+	return &Session{
+		ID:      now.Format("20060102-150405"),
+		Created: now,
+		Updated: now,
+	}
+}
+
+// validID matches session IDs, which name files in the session directory
+var validID = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// LoadSession reads a saved session by ID
+func LoadSession(root, id string) (*Session, error) {
+	if !validID.MatchString(id) {
+		return nil, fmt.Errorf("invalid chat session ID %q", id)
+	}
+	data, err := os.ReadFile(filepath.Join(SessionDir(root), id+".json"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("chat session %s not found", id)
+		}
+		return nil, fmt.Errorf("failed to read chat session: %w", err)
+	}
+
+	var session Session
+	if err := json.Unmarshal(data, &session); err != nil {
+		return nil, fmt.Errorf("failed to parse chat session %s: %w", id, err)
+	}
+	return &session, nil
+}
+
+// ListSessions returns the saved sessions of a project, most recent first
+func ListSessions(root string) ([]*Session, error) {
+	files, err := filepath.Glob(filepath.Join(SessionDir(root), "*.json"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list chat sessions: %w", err)
+	}
+
+	var sessions []*Session
+	for _, file := range files {
+		session, err := LoadSession(root, strings.TrimSuffix(filepath.Base(file), ".json"))
+		if err != nil {
+			continue
+		}
+		sessions = append(sessions, session)
+	}
+
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].Updated.After(sessions[j].Updated)
+	})
+	return sessions, nil
+}
+
+// Save writes the session to the project
+func (s *Session) Save(root string) error {
+	dir := SessionDir(root)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create chat directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal chat session: %w", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, s.ID+".json"), data, 0644); err != nil {
+		return fmt.Errorf("failed to write chat session: %w", err)
+	}
+	return nil
+}
+
+// Add appends a message to the session
+func (s *Session) Add(role, content string) {
+	now := time.Now()
+	s.Messages = append(s.Messages, Message{Role: role, Content: content, Time: now})
+	s.Updated = now
+}
+
+// LastExchange returns the latest question and the answer it got
+func (s *Session) LastExchange() (question, answer string, ok bool) {
+	for i := len(s.Messages) - 1; i > 0; i-- {
+		if s.Messages[i].Role == openai.ChatMessageRoleAssistant && s.Messages[i-1].Role == openai.ChatMessageRoleUser {
+			return s.Messages[i-1].Content, s.Messages[i].Content, true
+		}
+	}
+	return "", "", false
+}
+
+// LastItem returns the ID of the work item most recently created from the session
+func (s *Session) LastItem() string {
+	if len(s.Items) == 0 {
+		return ""
+	}
+	return s.Items[len(s.Items)-1]
+}
+
+// Title summarizes the session by its first question
+func (s *Session) Title() string {
+	for _, msg := range s.Messages {
+		if msg.Role == openai.ChatMessageRoleUser {
+			title := strings.Join(strings.Fields(msg.Content), " ")
+			if len(title) > 60 {
+				title = title[:60] + "..."
+			}
+			return title
+		}
+	}
+	return "(empty)"
+}
+
+// ChatMessages returns the recent conversation to send to the AI, after the system prompt
+func (s *Session) ChatMessages(system string) []openai.ChatCompletionMessage {
+	history := s.Messages
+	if len(history) > maxHistoryMessages {
+		history = history[len(history)-maxHistoryMessages:]
+	}
+
+	messages := []openai.ChatCompletionMessage{
+		{Role: openai.ChatMessageRoleSystem, Content: system},
+	}
+	for _, msg := range history {
+		messages = append(messages, openai.ChatCompletionMessage{
+			Role:    msg.Role,
+			Content: msg.Content,
+		})
+	}
+	return messages
+}
@@ -1,8 +1,15 @@
 package commands
 
 import (
+	"encoding/json"
 	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
 
+	"github.com/baudevs/yolo.baudevs.com/internal/ai"
 	"github.com/baudevs/yolo.baudevs.com/internal/config"
 	"github.com/baudevs/yolo.baudevs.com/internal/license"
 	"github.com/baudevs/yolo.baudevs.com/internal/messages"
@@ -20,6 +27,10 @@ func NewAICommand() *cobra.Command {
 	cmd.AddCommand(
 		newAIConfigCommand(),
 		newAIStatusCommand(),
+		newAIUsageCommand(),
+		newAIAuditCommand(),
+		newAIEvalCommand(),
+		newAIStyleCommand(),
 	)
 
 	return cmd
@@ -121,3 +132,168 @@ func newAIStatusCommand() *cobra.Command {
 		},
 	}
 }
+
+func newAIUsageCommand() *cobra.Command {
+	var since string
+	var by string
+	var outputJSON bool
+
+	cmd := &cobra.Command{
+		Use:   "usage",
+		Short: "Show AI token usage and cost",
+		Long: `Show tokens and estimated cost of AI requests recorded in the local usage ledger.
+
+Examples:
+  yolo ai usage
+  yolo ai usage --since 30d --by model
+  yolo ai usage --by project --json`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			window, err := parseSince(since)
+			if err != nil {
+				return err
+			}
+
+			records, err := ai.LoadUsage(time.Now().Add(-window))
+			if err != nil {
+				return fmt.Errorf("failed to load usage: %w", err)
+			}
+
+			summaries, err := ai.SummarizeUsage(records, by)
+			if err != nil {
+				return err
+			}
+
+			if outputJSON {
+				data, err := json.MarshalIndent(summaries, "", "  ")
+				if err != nil {
+					return fmt.Errorf("failed to marshal usage: %w", err)
+				}
+				fmt.Println(string(data))
+				return nil
+			}
+
+			if len(summaries) == 0 {
+				fmt.Printf("No AI usage recorded in the last %s\n", since)
+				return nil
+			}
+
+			fmt.Printf("📊 AI usage (last %s, by %s)\n\n", since, by)
+
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintf(w, "%s\tREQUESTS\tPROMPT\tCOMPLETION\tTOTAL\tCOST (USD)\n", strings.ToUpper(by))
+
+			var total ai.UsageSummary
+			for _, s := range summaries {
+				fmt.Fprintf(w, "%s\t%d\t%d\t%d\t%d\t$%.4f\n",
+					s.Key, s.Requests, s.PromptTokens, s.CompletionTokens, s.TotalTokens, s.Cost)
+				total.Requests += s.Requests
+				total.PromptTokens += s.PromptTokens
+				total.CompletionTokens += s.CompletionTokens
+				total.TotalTokens += s.TotalTokens
+				total.Cost += s.Cost
+			}
+			fmt.Fprintf(w, "TOTAL\t%d\t%d\t%d\t%d\t$%.4f\n",
+				total.Requests, total.PromptTokens, total.CompletionTokens, total.TotalTokens, total.Cost)
+
+			return w.Flush()
+		},
+	}
+
+	cmd.Flags().StringVar(&since, "since", "7d", "Time window to report (e.g. 24h, 7d, 4w)")
+	cmd.Flags().StringVar(&by, "by", "command", "Group by command, model or project")
+	cmd.Flags().BoolVar(&outputJSON, "json", false, "Output in JSON format")
+
+	return cmd
+}
+
+func newAIAuditCommand() *cobra.Command {
+	var since string
+	var showPayload bool
+	var outputJSON bool
+
+	cmd := &cobra.Command{
+		Use:   "audit",
+		Short: "Show what was sent to the AI",
+		Long: `Show the requests recorded in the local audit log, as they were sent to the AI
+after redaction, with the secrets that were masked in each.
+
+Examples:
+  yolo ai audit
+  yolo ai audit --since 24h --payload
+  yolo ai audit --json`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			window, err := parseSince(since)
+			if err != nil {
+				return err
+			}
+
+			records, err := ai.LoadAudit(time.Now().Add(-window))
+			if err != nil {
+				return fmt.Errorf("failed to load audit log: %w", err)
+			}
+
+			if outputJSON {
+				data, err := json.MarshalIndent(records, "", "  ")
+				if err != nil {
+					return fmt.Errorf("failed to marshal audit log: %w", err)
+				}
+				fmt.Println(string(data))
+				return nil
+			}
+
+			if len(records) == 0 {
+				fmt.Printf("No AI requests recorded in the last %s\n", since)
+				return nil
+			}
+
+			for _, r := range records {
+				fmt.Printf("%s  %-12s %-10s %-14s %6d bytes  %s\n",
+					r.Time.Local().Format("2006-01-02 15:04:05"), r.Command, r.Kind, r.Model,
+					len(r.Payload), formatRedactions(r.Redactions))
+				if showPayload {
+					fmt.Printf("\n%s\n\n", r.Payload)
+				}
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&since, "since", "7d", "Time window to report (e.g. 24h, 7d, 4w)")
+	cmd.Flags().BoolVar(&showPayload, "payload", false, "Print the payload of each request")
+	cmd.Flags().BoolVar(&outputJSON, "json", false, "Output in JSON format")
+
+	return cmd
+}
+
+// formatRedactions summarizes masked secrets, e.g. "masked: 2 jwt, 1 openai-key"
+func formatRedactions(redactions []ai.Redaction) string {
+	if len(redactions) == 0 {
+		return "nothing masked"
+	}
+
+	parts := make([]string, len(redactions))
+	for i, r := range redactions {
+		parts[i] = fmt.Sprintf("%d %s", r.Count, r.Rule)
+	}
+	return "masked: " + strings.Join(parts, ", ")
+}
+
+// parseSince parses a duration, additionally accepting day (d) and week (w) units
+func parseSince(s string) (time.Duration, error) {
+	unit := map[byte]time.Duration{'d': 24 * time.Hour, 'w': 7 * 24 * time.Hour}
+	if len(s) > 1 {
+		if mult, ok := unit[s[len(s)-1]]; ok {
+			n, err := strconv.Atoi(s[:len(s)-1])
+			if err != nil || n < 0 {
+				return 0, fmt.Errorf("invalid --since value: %s", s)
+			}
+			return time.Duration(n) * mult, nil
+		}
+	}
+
+	d, err := time.ParseDuration(s)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("invalid --since value: %s", s)
+	}
+	return d, nil
+}
@@ -0,0 +1,263 @@
+package commands
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strings"
+	"sync"
+	"text/tabwriter"
+
+	"github.com/baudevs/yolo.baudevs.com/internal/ai"
+	"github.com/baudevs/yolo.baudevs.com/internal/config"
+	"github.com/baudevs/yolo.baudevs.com/internal/eval"
+	"github.com/baudevs/yolo.baudevs.com/internal/license"
+	"github.com/spf13/cobra"
+)
+
+func newAIEvalCommand() *cobra.Command {
+	var (
+		model        string
+		baselinePath string
+		saveBaseline bool
+		tolerance    float64
+		concurrency  int
+		showOutput   bool
+		list         bool
+		outputJSON   bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "eval [suite...]",
+		Short: "Score prompts against sample inputs and compare with a baseline",
+		Long: `Run prompt suites against the configured model and score the outputs with
+rule-based checks: conventional commit format, subject length, number of
+children, required sections and patterns.
+
+Built-in suites cover commit messages, generated like yolo commit does, epic
+and task descriptions and feature/task breakdowns. Prompts are rendered
+without the repository's commit style and content language. Suites in yolo/eval/*.yml add to them, or replace
+the built-in suite of the same name.
+
+Save a baseline once the results look right, then run the eval after
+changing prompts or models: cases scoring lower than in the baseline are
+reported as regressions and the command fails.
+
+Examples:
+  yolo ai eval --list
+  yolo ai eval --save-baseline
+  yolo ai eval commit --model gpt-4o
+  yolo ai eval --tolerance 0.1 --json`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			suites, err := eval.LoadSuites()
+			if err != nil {
+				return err
+			}
+			suites, err = eval.Select(suites, args)
+			if err != nil {
+				return err
+			}
+
+			if list {
+				w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+				fmt.Fprintln(w, "SUITE\tPROMPT\tCASES\tSOURCE")
+				for _, s := range suites {
+					fmt.Fprintf(w, "%s\t%s\t%d\t%s\n", s.Name, s.Target(), len(s.Cases), s.Source)
+				}
+				return w.Flush()
+			}
+
+			// Load config
+			cfg, err := config.LoadConfig()
+			if err != nil {
+				return fmt.Errorf("failed to load config: %w", err)
+			}
+
+			// Create license manager
+			licenseManager, err := license.NewManager()
+			if err != nil {
+				return fmt.Errorf("failed to create license manager: %w", err)
+			}
+
+			// Create AI client
+			client, err := ai.NewClient(cfg, licenseManager)
+			if err != nil {
+				return fmt.Errorf("failed to create AI client: %w", err)
+			}
+			if model != "" {
+				client = client.WithModel(model)
+			}
+
+			if concurrency <= 0 {
+				concurrency = cfg.AI.Concurrency
+			}
+			if concurrency <= 0 {
+				concurrency = config.DefaultConcurrency
+			}
+
+			total := 0
+			for _, s := range suites {
+				total += len(s.Cases)
+			}
+			if !outputJSON {
+				fmt.Printf("🧪 Running %d suites (%d cases) against %s...\n", len(suites), total, client.Model())
+			}
+
+			var mu sync.Mutex
+			done := 0
+			report := eval.Run(cmd.Context(), client, suites, concurrency, func(suite, c string) {
+				if outputJSON {
+					return
+				}
+				mu.Lock()
+				defer mu.Unlock()
+				done++
+				fmt.Printf("  [%d/%d] %s/%s\n", done, total, suite, c)
+			})
+
+			baseline, err := eval.LoadBaseline(baselinePath)
+			if err != nil && !errors.Is(err, fs.ErrNotExist) {
+				return err
+			}
+			var regressions []eval.Regression
+			if baseline != nil {
+				regressions = eval.Compare(*baseline, report, tolerance)
+			}
+
+			if outputJSON {
+				data, err := json.MarshalIndent(struct {
+					eval.Report
+					Regressions []eval.Regression `json:"regressions"`
+				}{report, regressions}, "", "  ")
+				if err != nil {
+					return fmt.Errorf("failed to marshal eval report: %w", err)
+				}
+				fmt.Println(string(data))
+			} else {
+				printEvalReport(report, baseline, regressions, showOutput)
+			}
+
+			if saveBaseline {
+				if err := eval.SaveBaseline(baselinePath, report); err != nil {
+					return err
+				}
+				if !outputJSON {
+					fmt.Printf("\n💾 Baseline saved to %s\n", baselinePath)
+				}
+				return nil
+			}
+
+			if baseline == nil && !outputJSON {
+				fmt.Printf("\nNo baseline at %s yet: save one with --save-baseline\n", baselinePath)
+			}
+			if len(regressions) > 0 {
+				// Failing is the result here, not a usage mistake
+				cmd.SilenceUsage = true
+				return fmt.Errorf("%d cases regressed since the baseline", len(regressions))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&model, "model", "", "Model to evaluate (default: ai.model from the config)")
+	cmd.Flags().StringVar(&baselinePath, "baseline", eval.BaselinePath, "Baseline report to compare against")
+	cmd.Flags().BoolVar(&saveBaseline, "save-baseline", false, "Save this run as the new baseline")
+	cmd.Flags().Float64Var(&tolerance, "tolerance", 0, "Score drop per case tolerated before reporting a regression (0-1)")
+	cmd.Flags().IntVarP(&concurrency, "concurrency", "j", 0, "Maximum parallel AI requests (default: ai.concurrency from the config, or 4)")
+	cmd.Flags().BoolVar(&showOutput, "output", false, "Print the output of cases that failed a check")
+	cmd.Flags().BoolVar(&list, "list", false, "List the suites without running them")
+	cmd.Flags().BoolVar(&outputJSON, "json", false, "Output in JSON format")
+
+	return cmd
+}
+
+// printEvalReport prints suite scores next to the baseline, the failed
+// checks of every case and the regressions
+func printEvalReport(report eval.Report, baseline *eval.Report, regressions []eval.Regression, showOutput bool) {
+	before := make(map[string]float64)
+	if baseline != nil {
+		for _, s := range baseline.Suites {
+			before[s.Name] = s.Score
+		}
+	}
+
+	if baseline != nil && baseline.Model != report.Model {
+		fmt.Printf("\n⚠️  The baseline was recorded with %s on %s\n", baseline.Model, baseline.Time.Local().Format("2006-01-02"))
+	}
+
+	fmt.Println()
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "SUITE\tSCORE\tBASELINE\tCASES PASSED")
+	for _, s := range report.Suites {
+		passed := 0
+		for _, c := range s.Cases {
+			if c.Score == 1 {
+				passed++
+			}
+		}
+		old := "-"
+		if score, ok := before[s.Name]; ok {
+			old = formatScore(score)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%d/%d\n", s.Name, formatScore(s.Score), old, passed, len(s.Cases))
+	}
+	fmt.Fprintf(w, "TOTAL\t%s\t\t\n", formatScore(report.Score()))
+	w.Flush()
+
+	var failures []string
+	for _, s := range report.Suites {
+		for _, c := range s.Cases {
+			if c.Error != "" {
+				failures = append(failures, fmt.Sprintf("  ❌ %s/%s: %s", s.Name, c.Name, c.Error))
+				continue
+			}
+			for _, r := range c.Checks {
+				if !r.Passed {
+					failures = append(failures, fmt.Sprintf("  ❌ %s/%s: %s: %s", s.Name, c.Name, r.Check, r.Detail))
+				}
+			}
+			if showOutput && c.Score < 1 {
+				failures = append(failures, indent(strings.TrimSpace(c.Output), "       "))
+			}
+		}
+	}
+	if len(failures) > 0 {
+		fmt.Println("\nFailed checks:")
+		fmt.Println(strings.Join(failures, "\n"))
+	}
+
+	if len(regressions) > 0 {
+		fmt.Println("\n📉 Regressions since the baseline:")
+		for _, r := range regressions {
+			detail := strings.Join(r.Failed, "; ")
+			if r.Error != "" {
+				detail = r.Error
+			}
+			fmt.Printf("  %s/%s: %s → %s", r.Suite, r.Case, formatScore(r.Before), formatScore(r.After))
+			if detail != "" {
+				fmt.Printf(" (%s)", detail)
+			}
+			fmt.Println()
+		}
+	} else if baseline != nil {
+		fmt.Println("\n✅ No regressions since the baseline")
+	}
+}
+
+// formatScore formats a 0-1 score as a percentage
+func formatScore(score float64) string {
+	return fmt.Sprintf("%.0f%%", score*100)
+}
+
+// indent prefixes the non-empty lines of text
+func indent(text, prefix string) string {
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		if line != "" {
+			lines[i] = prefix + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
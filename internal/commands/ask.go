@@ -1,22 +1,42 @@
 package commands
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/baudevs/yolo.baudevs.com/internal/ai"
 	"github.com/baudevs/yolo.baudevs.com/internal/config"
 	"github.com/baudevs/yolo.baudevs.com/internal/license"
+	"github.com/baudevs/yolo.baudevs.com/internal/retrieval"
 	"github.com/spf13/cobra"
 )
 
 // NewAskCommand returns a new ask command
 func NewAskCommand() *cobra.Command {
-	var query string
+	var (
+		query         string
+		noContext     bool
+		useEmbeddings bool
+		budget        int
+		candidates    int
+		agentMode     bool
+		maxSteps      int
+	)
 
 	cmd := &cobra.Command{
 		Use:   "ask",
 		Short: "Ask the AI a question",
-		Long:  "Ask the AI a programming-related question",
+		Long: `Ask the AI a question about your project.
+
+The epics, features, tasks, STRATEGY.md, README and source files most relevant
+to the question are sent along with it, within a token budget, and the answer
+cites the files it used. Use --no-context to ask a general question.
+
+With --agent the AI looks things up itself with project tools: listing,
+searching and showing work items, reading files and the git log. It can also
+create tasks and change statuses, after you confirm each change.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			// Load config
 			cfg, err := config.LoadConfig()
@@ -38,21 +58,125 @@ func NewAskCommand() *cobra.Command {
 
 			// Get query from args if not provided as flag
 			if query == "" && len(args) > 0 {
-				query = args[0]
+				query = strings.Join(args, " ")
+			}
+			if query == "" {
+				return fmt.Errorf("please provide a question")
+			}
+
+			if agentMode {
+				response, err := runAskAgent(cmd.Context(), client, query, maxSteps)
+				if err != nil {
+					return fmt.Errorf("failed to get response: %w", err)
+				}
+				fmt.Printf("\n%s\n", response)
+				return nil
+			}
+
+			var sources []retrieval.Result
+			prompt := ""
+			if !noContext {
+				var embed retrieval.EmbedFunc
+				if useEmbeddings {
+					embed = client.Embed
+				}
+				prompt, sources, err = buildAskPrompt(cmd.Context(), query, budget, candidates, embed)
+				if err != nil {
+					return err
+				}
+			}
+			if prompt == "" {
+				prompt, err = ai.RenderPrompt("ask", ai.PromptData{"Question": query})
+				if err != nil {
+					return err
+				}
 			}
 
 			// Ask the question
-			response, err := client.Ask(cmd.Context(), query)
+			response, err := client.Ask(cmd.Context(), prompt)
 			if err != nil {
 				return fmt.Errorf("failed to get response: %w", err)
 			}
 
 			fmt.Printf("\n%s\n", response)
+
+			if len(sources) > 0 {
+				fmt.Println("\n📚 Sources:")
+				for _, source := range sources {
+					fmt.Printf("  - %s\n", source.Citation())
+				}
+			}
 			return nil
 		},
 	}
 
 	cmd.Flags().StringVarP(&query, "query", "q", "", "Question to ask")
+	cmd.Flags().BoolVar(&noContext, "no-context", false, "Ask without project context")
+	cmd.Flags().BoolVar(&useEmbeddings, "embeddings", false, "Rerank context with embeddings (extra API call)")
+	cmd.Flags().IntVar(&budget, "budget", 3000, "Maximum tokens of project context to send")
+	cmd.Flags().IntVar(&candidates, "candidates", 20, "Number of keyword matches considered for the context")
+	cmd.Flags().BoolVar(&agentMode, "agent", false, "Let the AI use project tools, confirming any change")
+	cmd.Flags().IntVar(&maxSteps, "max-steps", ai.DefaultAgentSteps, "Maximum AI turns in agent mode")
 
 	return cmd
 }
+
+// buildAskPrompt retrieves the project context relevant to the question.
+// It returns an empty prompt when nothing in the project matches.
+func buildAskPrompt(ctx context.Context, query string, budget, candidates int, embed retrieval.EmbedFunc) (string, []retrieval.Result, error) {
+	root, err := getProjectRoot()
+	if err != nil {
+		root = "."
+	}
+
+	docs, err := retrieval.Collect(root)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to collect project context: %w", err)
+	}
+
+	// Files excluded from redaction are never sent, not even in part
+	redactor, err := ai.DefaultRedactor()
+	if err != nil {
+		return "", nil, err
+	}
+	allowed := docs[:0]
+	for _, doc := range docs {
+		if !redactor.ExcludesPath(doc.Path) {
+			allowed = append(allowed, doc)
+		}
+	}
+	docs = allowed
+
+	results := retrieval.NewIndex(docs).Search(query, candidates)
+	if len(results) == 0 {
+		return "", nil, nil
+	}
+
+	if embed != nil {
+		reranked, err := retrieval.Rerank(ctx, embed, query, results)
+		switch {
+		case err == nil:
+			results = reranked
+		case errors.Is(err, ai.ErrEmbeddingsUnsupported):
+			fmt.Println("⚠️  Embeddings are not supported by the AI provider, using keyword matches only")
+		default:
+			return "", nil, err
+		}
+	}
+
+	projectContext, sources := retrieval.Pack(results, budget)
+	if len(sources) == 0 {
+		return "", nil, nil
+	}
+
+	prompt, err := ai.RenderPrompt("ask.context", ai.PromptData{
+		"Question": query,
+		"Context":  projectContext,
+		"Example":  sources[0].Citation(),
+	})
+	if err != nil {
+		return "", nil, err
+	}
+
+	return prompt, sources, nil
+}
@@ -0,0 +1,312 @@
+package commands
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/baudevs/yolo.baudevs.com/internal/ai"
+	"github.com/baudevs/yolo.baudevs.com/internal/relationships"
+)
+
+const (
+	// maxFileLines bounds how much of a file the agent reads at once
+	maxFileLines = 300
+	// maxGitLogEntries bounds the git log tool
+	maxGitLogEntries = 50
+)
+
+type listItemsArgs struct {
+	Type   string `json:"type,omitempty" enum:"epic,feature,task" description:"Only items of this type"`
+	Status string `json:"status,omitempty" description:"Only items with this status, e.g. planning, in-progress or done"`
+	Parent string `json:"parent,omitempty" description:"Only items linked to this parent epic or feature ID, e.g. E002"`
+}
+
+type searchItemsArgs struct {
+	Query string `json:"query" description:"Words describing the items to find"`
+	Limit int    `json:"limit,omitempty" description:"Maximum number of results, 10 by default"`
+}
+
+type showItemArgs struct {
+	ID string `json:"id" description:"Work item ID, e.g. T003"`
+}
+
+type createTaskArgs struct {
+	Title       string `json:"title" description:"Short task title"`
+	Description string `json:"description" description:"Actionable description with success criteria"`
+	Parent      string `json:"parent,omitempty" description:"Feature or epic ID to link the task to"`
+}
+
+type setStatusArgs struct {
+	ID     string `json:"id" description:"Work item ID, e.g. T003"`
+	Status string `json:"status" enum:"planning,in-progress,done" description:"New status"`
+}
+
+type readFileArgs struct {
+	Path      string `json:"path" description:"File path relative to the project root"`
+	StartLine int    `json:"start_line,omitempty" description:"First line to read, 1 by default"`
+	EndLine   int    `json:"end_line,omitempty" description:"Last line to read"`
+}
+
+type gitLogArgs struct {
+	Path  string `json:"path,omitempty" description:"Only commits touching this path"`
+	Grep  string `json:"grep,omitempty" description:"Only commits whose message contains this text"`
+	Limit int    `json:"limit,omitempty" description:"Maximum number of commits, 20 by default"`
+}
+
+// projectTools are the operations yolo ask --agent offers the model
+func projectTools(root string, relManager *relationships.RelationshipManager) []ai.Tool {
+	loadItems := func() ([]relationships.WorkItem, error) {
+		return relManager.LoadWorkItems(relationships.Epic, relationships.Feature, relationships.Task)
+	}
+	findItem := func(id string) (*relationships.WorkItem, error) {
+		items, err := loadItems()
+		if err != nil {
+			return nil, err
+		}
+		for i := range items {
+			if strings.EqualFold(items[i].ID, id) {
+				return &items[i], nil
+			}
+		}
+		return nil, fmt.Errorf("work item %s not found", id)
+	}
+
+	return []ai.Tool{
+		ai.NewTool("list_items", "List epics, features and tasks, optionally filtered", false,
+			func(ctx context.Context, args listItemsArgs) (string, error) {
+				items, err := loadItems()
+				if err != nil {
+					return "", err
+				}
+
+				var sb strings.Builder
+				for _, item := range items {
+					if args.Type != "" && !strings.EqualFold(string(item.Type), args.Type) {
+						continue
+					}
+					if args.Status != "" && !strings.EqualFold(item.Status, args.Status) {
+						continue
+					}
+					if args.Parent != "" && !item.HasParent(strings.ToUpper(args.Parent)) {
+						continue
+					}
+					fmt.Fprintf(&sb, "[%s] %s (%s, %s)\n", item.ID, item.Title, strings.ToLower(string(item.Type)), item.Status)
+				}
+				if sb.Len() == 0 {
+					return "No matching work items.", nil
+				}
+				return sb.String(), nil
+			}),
+
+		ai.NewTool("search_items", "Find the work items most similar to a description", false,
+			func(ctx context.Context, args searchItemsArgs) (string, error) {
+				items, err := loadItems()
+				if err != nil {
+					return "", err
+				}
+				if args.Limit <= 0 {
+					args.Limit = 10
+				}
+
+				idx := relationships.NewSimilarityIndex(items)
+				var matches []relationships.Match
+				for _, itemType := range []relationships.WorkItemType{relationships.Epic, relationships.Feature, relationships.Task} {
+					matches = append(matches, idx.Similar(args.Query, itemType, args.Limit)...)
+				}
+				sort.SliceStable(matches, func(i, j int) bool { return matches[i].Score > matches[j].Score })
+				if len(matches) > args.Limit {
+					matches = matches[:args.Limit]
+				}
+
+				var sb strings.Builder
+				for _, m := range matches {
+					fmt.Fprintf(&sb, "[%s] %s (%s, %s, score %.2f)\n", m.Item.ID, m.Item.Title,
+						strings.ToLower(string(m.Item.Type)), m.Item.Status, m.Score)
+				}
+				if sb.Len() == 0 {
+					return "No similar work items.", nil
+				}
+				return sb.String(), nil
+			}),
+
+		ai.NewTool("show_item", "Show the full markdown of a work item", false,
+			func(ctx context.Context, args showItemArgs) (string, error) {
+				item, err := findItem(args.ID)
+				if err != nil {
+					return "", err
+				}
+				return item.Content, nil
+			}),
+
+		ai.NewTool("create_task", "Create a task, linked to a parent feature or epic", true,
+			func(ctx context.Context, args createTaskArgs) (string, error) {
+				var parent *relationships.WorkItem
+				if args.Parent != "" {
+					var err error
+					if parent, err = findItem(args.Parent); err != nil {
+						return "", err
+					}
+				}
+
+				item, err := relManager.CreateItem(relationships.Task, args.Title, args.Description, parent)
+				if err != nil {
+					return "", err
+				}
+				return fmt.Sprintf("Created task %s at %s", item.ID, item.Path), nil
+			}),
+
+		ai.NewTool("set_status", "Change the status of a work item", true,
+			func(ctx context.Context, args setStatusArgs) (string, error) {
+				item, err := findItem(args.ID)
+				if err != nil {
+					return "", err
+				}
+				if err := relManager.SetStatus(*item, args.Status); err != nil {
+					return "", err
+				}
+				return fmt.Sprintf("%s is now %s (was %s)", item.ID, args.Status, item.Status), nil
+			}),
+
+		ai.NewTool("read_file", "Read lines of a project file", false,
+			func(ctx context.Context, args readFileArgs) (string, error) {
+				return readProjectFile(root, args)
+			}),
+
+		ai.NewTool("git_log", "Show recent commits", false,
+			func(ctx context.Context, args gitLogArgs) (string, error) {
+				if args.Limit <= 0 || args.Limit > maxGitLogEntries {
+					args.Limit = 20
+				}
+
+				gitArgs := []string{"log", "--date=short", "--pretty=format:%h %ad %an %s", "-n", strconv.Itoa(args.Limit)}
+				if args.Grep != "" {
+					gitArgs = append(gitArgs, "--fixed-strings", "--regexp-ignore-case", "--grep="+args.Grep)
+				}
+				if args.Path != "" {
+					gitArgs = append(gitArgs, "--", args.Path)
+				}
+
+				cmd := exec.CommandContext(ctx, "git", gitArgs...)
+				cmd.Dir = root
+				output, err := cmd.CombinedOutput()
+				if err != nil {
+					return "", fmt.Errorf("git log failed: %s", strings.TrimSpace(string(output)))
+				}
+				if len(output) == 0 {
+					return "No matching commits.", nil
+				}
+				return string(output), nil
+			}),
+	}
+}
+
+// readProjectFile reads a line range of a file inside the project,
+// refusing files excluded from AI requests
+func readProjectFile(root string, args readFileArgs) (string, error) {
+	absRoot, err := filepath.Abs(root)
+	if err != nil {
+		return "", err
+	}
+	if absRoot, err = filepath.EvalSymlinks(absRoot); err != nil {
+		return "", err
+	}
+	path := filepath.Join(absRoot, filepath.FromSlash(args.Path))
+	rel, err := filepath.Rel(absRoot, path)
+	if err != nil || outsideRoot(rel) {
+		return "", fmt.Errorf("%s is outside the project", args.Path)
+	}
+
+	// A symlink inside the project may point outside of it, or to an excluded file
+	resolved, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to open %s: %w", args.Path, err)
+	}
+	resolvedRel, err := filepath.Rel(absRoot, resolved)
+	if err != nil || outsideRoot(resolvedRel) {
+		return "", fmt.Errorf("%s is outside the project", args.Path)
+	}
+
+	redactor, err := ai.DefaultRedactor()
+	if err != nil {
+		return "", err
+	}
+	if redactor.ExcludesPath(rel) || redactor.ExcludesPath(resolvedRel) {
+		return "", fmt.Errorf("%s is excluded from AI requests", args.Path)
+	}
+	path = resolved
+
+	f, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to open %s: %w", args.Path, err)
+	}
+	defer f.Close()
+
+	start := args.StartLine
+	if start < 1 {
+		start = 1
+	}
+	end := args.EndLine
+	if end < start || end-start >= maxFileLines {
+		end = start + maxFileLines - 1
+	}
+
+	var sb strings.Builder
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	n := 0
+	for scanner.Scan() {
+		n++
+		if n < start {
+			continue
+		}
+		if n > end {
+			fmt.Fprintf(&sb, "... (more lines, continue from line %d)\n", n)
+			break
+		}
+		fmt.Fprintf(&sb, "%4d  %s\n", n, scanner.Text())
+	}
+	if err := scanner.Err(); err != nil {
+		return "", fmt.Errorf("failed to read %s: %w", args.Path, err)
+	}
+	if sb.Len() == 0 {
+		return fmt.Sprintf("%s has %d lines", args.Path, n), nil
+	}
+	return sb.String(), nil
+}
+
+// outsideRoot reports whether a path relative to the project root leaves it
+func outsideRoot(rel string) bool {
+	return rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// runAskAgent answers the question with the project tools
+func runAskAgent(ctx context.Context, client *ai.Client, query string, maxSteps int) (string, error) {
+	root, err := getProjectRoot()
+	if err != nil {
+		root = "."
+	}
+
+	system, err := ai.RenderPrompt("ask.agent", nil)
+	if err != nil {
+		return "", err
+	}
+
+	agent := ai.NewAgent(client, projectTools(root, relationships.NewManager(client)))
+	agent.MaxSteps = maxSteps
+	agent.OnCall = func(tool ai.Tool, arguments string) {
+		fmt.Printf("🔧 %s %s\n", tool.Name, arguments)
+	}
+	agent.Confirm = func(tool ai.Tool, arguments string) bool {
+		ok, err := confirm(fmt.Sprintf("⚠️  Allow %s to change the project?", tool.Name))
+		return err == nil && ok
+	}
+
+	return agent.Run(ctx, system, query)
+}
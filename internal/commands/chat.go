@@ -0,0 +1,365 @@
+package commands
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/baudevs/yolo.baudevs.com/internal/ai"
+	"github.com/baudevs/yolo.baudevs.com/internal/chat"
+	"github.com/baudevs/yolo.baudevs.com/internal/config"
+	"github.com/baudevs/yolo.baudevs.com/internal/license"
+	"github.com/baudevs/yolo.baudevs.com/internal/relationships"
+	"github.com/sashabaranov/go-openai"
+	"github.com/spf13/cobra"
+)
+
+const chatHelp = `Commands:
+  /task [title]           Create a task from the last exchange
+  /feature [title]        Create a feature from the last exchange
+  /link [ID] <parent-ID>  Link a work item (default: the last one created) to a parent
+  /save-to-sprint [note]  Add the last exchange to sprint.current.md
+  /history                Show the conversation so far
+  /help                   Show this help
+  /exit                   Save and leave the session`
+
+// chatItemDraft is the work item the AI drafts from a chat exchange
+type chatItemDraft struct {
+	Title       string `json:"title" description:"Short work item title, under 80 characters"`
+	Description string `json:"description" description:"Specific, actionable description with success criteria"`
+}
+
+// chatSession is a running yolo chat REPL
+type chatSession struct {
+	root       string
+	session    *chat.Session
+	client     *ai.Client
+	relManager *relationships.RelationshipManager
+}
+
+// NewChatCommand returns a new chat command
+func NewChatCommand() *cobra.Command {
+	var (
+		resume       string
+		continueLast bool
+		list         bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "chat",
+		Short: "Brainstorm with the AI in an interactive session",
+		Long: `Start an interactive chat session about your project.
+
+The conversation is saved under .yolo/chat/ in the project and can be resumed
+later by its session ID. Slash commands turn the last exchange into work items
+or sprint journal entries:
+
+` + chatHelp + `
+
+Examples:
+  yolo chat
+  yolo chat --list
+  yolo chat --resume 20240131-142530
+  yolo chat --continue`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			root, err := getProjectRoot()
+			if err != nil {
+				root = "."
+			}
+
+			if list {
+				return listChatSessions(root)
+			}
+
+			session := chat.NewSession()
+			switch {
+			case resume != "":
+				if session, err = chat.LoadSession(root, resume); err != nil {
+					return err
+				}
+			case continueLast:
+				sessions, err := chat.ListSessions(root)
+				if err != nil {
+					return err
+				}
+				if len(sessions) == 0 {
+					return fmt.Errorf("no chat session to continue")
+				}
+				session = sessions[0]
+			}
+
+			// Load config
+			cfg, err := config.LoadConfig()
+			if err != nil {
+				return fmt.Errorf("failed to load config: %w", err)
+			}
+
+			// Create license manager
+			licenseManager, err := license.NewManager()
+			if err != nil {
+				return fmt.Errorf("failed to create license manager: %w", err)
+			}
+
+			// Create AI client
+			client, err := ai.NewClient(cfg, licenseManager)
+			if err != nil {
+				return fmt.Errorf("failed to create AI client: %w", err)
+			}
+
+			c := &chatSession{
+				root:       root,
+				session:    session,
+				client:     client,
+				relManager: relationships.NewManager(client),
+			}
+			return c.run(cmd.Context())
+		},
+	}
+
+	cmd.Flags().StringVarP(&resume, "resume", "r", "", "Resume the chat session with this ID")
+	cmd.Flags().BoolVarP(&continueLast, "continue", "c", false, "Resume the most recent chat session")
+	cmd.Flags().BoolVarP(&list, "list", "l", false, "List saved chat sessions")
+	addLanguageFlag(cmd)
+
+	return cmd
+}
+
+func listChatSessions(root string) error {
+	sessions, err := chat.ListSessions(root)
+	if err != nil {
+		return err
+	}
+	if len(sessions) == 0 {
+		fmt.Println("No chat sessions yet. Start one with: yolo chat")
+		return nil
+	}
+
+	for _, s := range sessions {
+		fmt.Printf("%s  %s  %3d messages  %s\n",
+			s.ID, s.Updated.Format("2006-01-02 15:04"), len(s.Messages), s.Title())
+	}
+	return nil
+}
+
+func (c *chatSession) run(ctx context.Context) error {
+	system, err := ai.RenderPrompt("chat.system", nil)
+	if err != nil {
+		return err
+	}
+
+	if len(c.session.Messages) > 0 {
+		fmt.Printf("💬 Resuming session %s (%d messages)\n", c.session.ID, len(c.session.Messages))
+	} else {
+		fmt.Printf("💬 Chat session %s started, type /help for commands\n", c.session.ID)
+	}
+
+	scanner := bufio.NewScanner(os.Stdin)
+	for {
+		fmt.Print("\nyou> ")
+		if !scanner.Scan() {
+			break
+		}
+
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		if strings.HasPrefix(line, "/") {
+			if line == "/exit" || line == "/quit" {
+				break
+			}
+			if err := c.handleCommand(ctx, line); err != nil {
+				fmt.Printf("❌ %v\n", err)
+			}
+			continue
+		}
+
+		c.session.Add(openai.ChatMessageRoleUser, line)
+		reply, err := c.client.Chat(ctx, c.session.ChatMessages(system))
+		if err != nil {
+			// Drop the unanswered question so it is not replayed as history
+			c.session.Messages = c.session.Messages[:len(c.session.Messages)-1]
+			fmt.Printf("❌ failed to get response: %v\n", err)
+			continue
+		}
+		c.session.Add(openai.ChatMessageRoleAssistant, reply)
+		fmt.Printf("\nyolo> %s\n", reply)
+
+		if err := c.session.Save(c.root); err != nil {
+			fmt.Printf("⚠️  %v\n", err)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read input: %w", err)
+	}
+
+	if len(c.session.Messages) == 0 {
+		return nil
+	}
+	if err := c.session.Save(c.root); err != nil {
+		return err
+	}
+	fmt.Printf("\n👋 Session saved, resume it with: yolo chat --resume %s\n", c.session.ID)
+	return nil
+}
+
+func (c *chatSession) handleCommand(ctx context.Context, line string) error {
+	fields := strings.Fields(line)
+	arg := strings.TrimSpace(strings.TrimPrefix(line, fields[0]))
+
+	switch fields[0] {
+	case "/help":
+		fmt.Println(chatHelp)
+	case "/history":
+		for _, msg := range c.session.Messages {
+			speaker := "you"
+			if msg.Role == openai.ChatMessageRoleAssistant {
+				speaker = "yolo"
+			}
+			fmt.Printf("\n%s> %s\n", speaker, msg.Content)
+		}
+	case "/task":
+		return c.createItem(ctx, relationships.Task, arg)
+	case "/feature":
+		return c.createItem(ctx, relationships.Feature, arg)
+	case "/link":
+		return c.link(fields[1:])
+	case "/save-to-sprint":
+		return c.saveToSprint(arg)
+	default:
+		return fmt.Errorf("unknown command %s, type /help for the list", fields[0])
+	}
+	return nil
+}
+
+// createItem drafts a work item from the last exchange and links it to the best parent
+func (c *chatSession) createItem(ctx context.Context, itemType relationships.WorkItemType, title string) error {
+	question, answer, ok := c.session.LastExchange()
+	if !ok {
+		return fmt.Errorf("nothing to turn into a %s yet, ask something first", strings.ToLower(string(itemType)))
+	}
+
+	prompt, err := ai.RenderPrompt("chat.item", ai.PromptData{
+		"ItemType": strings.ToLower(string(itemType)),
+		"Question": question,
+		"Answer":   answer,
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("🤖 Drafting %s...\n", strings.ToLower(string(itemType)))
+	draft, err := ai.GenerateStructured[chatItemDraft](ctx, c.client.Provider(), ai.StructuredRequest{
+		Model:       c.client.Model(),
+		Messages:    []openai.ChatCompletionMessage{{Role: openai.ChatMessageRoleUser, Content: prompt}},
+		Name:        "create_work_item",
+		Description: "Create a work item from the conversation",
+	})
+	if err != nil {
+		return fmt.Errorf("failed to draft %s: %w", strings.ToLower(string(itemType)), err)
+	}
+	if title != "" {
+		draft.Title = title
+	}
+
+	items, err := c.relManager.LoadWorkItems(relationships.Epic, relationships.Feature, relationships.Task)
+	if err != nil {
+		return fmt.Errorf("failed to load work items: %w", err)
+	}
+
+	// Prompting would fight the REPL for stdin, so only warn
+	if err := checkDuplicates(itemType, draft.Title+"\n"+draft.Description, items, true); err != nil {
+		return err
+	}
+
+	parent, _, err := c.relManager.FindOrCreateParent(ctx, itemType, draft.Description, items)
+	if err != nil {
+		return fmt.Errorf("failed to find parent: %w", err)
+	}
+
+	item, err := c.relManager.CreateItem(itemType, draft.Title, draft.Description, parent)
+	if err != nil {
+		return err
+	}
+
+	c.session.Items = append(c.session.Items, item.ID)
+	if err := c.session.Save(c.root); err != nil {
+		fmt.Printf("⚠️  %v\n", err)
+	}
+
+	fmt.Printf("✨ Created %s %s: %s\n", strings.ToLower(string(itemType)), item.ID, item.Title)
+	if parent != nil {
+		fmt.Printf("🔗 Linked to %s: [%s] %s\n", strings.ToLower(string(parent.Type)), parent.ID, parent.Title)
+	} else {
+		fmt.Printf("💡 No matching parent found, link one with: /link %s <parent-ID>\n", item.ID)
+	}
+	return nil
+}
+
+// link connects a work item to a parent, defaulting to the last item created in the session
+func (c *chatSession) link(args []string) error {
+	var childID, parentID string
+	switch len(args) {
+	case 1:
+		childID, parentID = c.session.LastItem(), args[0]
+		if childID == "" {
+			return fmt.Errorf("no work item created in this session, use /link <ID> <parent-ID>")
+		}
+	case 2:
+		childID, parentID = args[0], args[1]
+	default:
+		return fmt.Errorf("usage: /link [ID] <parent-ID>")
+	}
+
+	items, err := c.relManager.LoadWorkItems(relationships.Epic, relationships.Feature, relationships.Task)
+	if err != nil {
+		return fmt.Errorf("failed to load work items: %w", err)
+	}
+
+	var child, parent *relationships.WorkItem
+	for i := range items {
+		switch items[i].ID {
+		case childID:
+			child = &items[i]
+		case parentID:
+			parent = &items[i]
+		}
+	}
+	if child == nil {
+		return fmt.Errorf("work item %s not found", childID)
+	}
+	if parent == nil {
+		return fmt.Errorf("work item %s not found", parentID)
+	}
+
+	if err := c.relManager.Link(*child, *parent); err != nil {
+		return err
+	}
+
+	fmt.Printf("🔗 Linked [%s] %s to [%s] %s\n", child.ID, child.Title, parent.ID, parent.Title)
+	return nil
+}
+
+// saveToSprint records the last exchange in the sprint journal
+func (c *chatSession) saveToSprint(note string) error {
+	question, answer, ok := c.session.LastExchange()
+	if !ok {
+		return fmt.Errorf("nothing to save yet, ask something first")
+	}
+
+	var sb strings.Builder
+	if note != "" {
+		sb.WriteString(note + "\n\n")
+	}
+	fmt.Fprintf(&sb, "**Question:** %s\n\n**Answer:**\n%s\n\n_From chat session %s_", question, answer, c.session.ID)
+
+	if err := appendSprintEntry(c.root, sb.String()); err != nil {
+		return err
+	}
+
+	fmt.Println("✨ Added the last exchange to sprint.current.md")
+	return nil
+}
@@ -3,13 +3,16 @@ package commands
 import (
 	"bufio"
 	"bytes"
+	"errors"
 	"fmt"
 	"os/exec"
 	"strings"
 
 	"github.com/baudevs/yolo.baudevs.com/internal/ai"
 	"github.com/baudevs/yolo.baudevs.com/internal/config"
+	"github.com/baudevs/yolo.baudevs.com/internal/git"
 	"github.com/baudevs/yolo.baudevs.com/internal/license"
+	"github.com/baudevs/yolo.baudevs.com/internal/models"
 	"github.com/spf13/cobra"
 )
 
@@ -18,15 +21,59 @@ func NewCommitCommand() *cobra.Command {
 	var message string
 	var stageAll bool
 	var summarizedDiff bool
+	var skipReview bool
+	var splitCommits bool
+	var tasks []string
+	var coAuthors []string
+	var signOff bool
+	var amend bool
+	var syncAfter bool
+	var force bool
 
 	cmd := &cobra.Command{
 		Use:   "commit",
 		Short: "Create a commit message",
 		Long: `Generate an AI-powered commit message based on staged changes.
 
-For large changes, you can use --summarized to send only file names and line counts
-to the AI instead of full diffs. This helps when you hit AI token limits.`,
+Large diffs are split at file and hunk boundaries, most significant files
+first: source code comes before tests, config and docs, while lockfiles,
+generated, vendored, binary and deleted files are only described by their
+line counts, like any file that does not fit. You can use --summarized to
+send only file names and line counts to the AI instead of full diffs.
+
+Before committing, the generated message is shown and you can accept it, edit
+it in $EDITOR, regenerate it (e.g. "r focus on the parser change") or abort.
+Use --yes to commit without the review, e.g. in scripts.
+
+With --split, the staged hunks are grouped into atomic commits, each with its
+own message. After confirmation the commits are created one by one from the
+staged changes only; if a step fails they are undone and everything is staged
+again as before.
+
+Commits are linked to the tasks named with --task, in the branch name (e.g.
+feat/T012-login) or whose files in yolo/tasks are staged: the message gets a
+"Refs: T012" trailer and the commit is added to the task's activity.
+
+Messages follow the style of the repository's history: the types, scopes,
+subject style and trailers of recent commits are added to the prompts (see
+"yolo ai style"), and scopes never used before are pointed out. They are
+written in the project's commit.format: conventional (default), gitmoji,
+angular (with the ticket as a prefix, e.g. "[T012] feat: ...") or template,
+rendering the project's own commit.template. --co-author and --signoff add
+Co-authored-by and Signed-off-by trailers.
+
+With --amend, the message is generated again for the last commit and the
+staged changes together, keeping its issue refs, co-authors and sign-offs.
+See "yolo squash" to combine several commits.
+
+With --sync, the branch is then rebased on its upstream and pushed, like with
+"yolo sync".`,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			syncOpts := models.CommitOptions{NoSync: !syncAfter, Force: force}
+			if force && syncOpts.NoSync {
+				return fmt.Errorf("--force only applies with --sync")
+			}
+
 			// Load config
 			cfg, err := config.LoadConfig()
 			if err != nil {
@@ -52,67 +99,152 @@ to the AI instead of full diffs. This helps when you hit AI token limits.`,
 				}
 			}
 
+			var opts commitOptions
+			if opts.linked, err = linkedTasks(tasks); err != nil {
+				return err
+			}
+			if opts.trailers, err = commitTrailers(coAuthors, signOff); err != nil {
+				return err
+			}
+			if opts.formatter, err = commitFormatter(); err != nil {
+				return err
+			}
+
+			// Learned after staging, so a refreshed style cache is not committed
+			if message == "" {
+				opts.style = applyCommitStyle()
+			}
+
+			if splitCommits {
+				if message != "" || summarizedDiff || amend {
+					return fmt.Errorf("--split cannot be combined with --message, --summarized or --amend")
+				}
+				err := runSplitCommit(cmd.Context(), client, opts, skipReview)
+				if errors.Is(err, errCommitAborted) {
+					return nil
+				}
+				if err != nil {
+					return err
+				}
+				return syncBranch(syncOpts)
+			}
+
+			// An amended commit is described together with the staged changes
+			var base string
+			var amended []string
+			if amend {
+				if base, err = amendBase(); err != nil {
+					return err
+				}
+				previous, err := git.Run("", "log", "-1", "--format=%B")
+				if err != nil {
+					return fmt.Errorf("failed to read the commit to amend: %w", err)
+				}
+				opts.trailers = append(keptTrailers(previous), opts.trailers...)
+				if hash, err := headCommit(); err == nil {
+					amended = append(amended, hash)
+				}
+				warnIfPushed("HEAD")
+			}
+
 			// Get the changes info
 			var diff string
 			var diffErr error
 			if summarizedDiff {
-				diff, diffErr = getSummarizedDiff()
+				diff, diffErr = getSummarizedDiff(base)
 			} else {
-				diff, diffErr = getFullDiff()
-
-				// If the diff is too large, warn the user and suggest using summarized mode
-				if len(diff) > 12000 { // Conservative limit for GPT-4
-					fmt.Println("\n⚠️  Warning: Large diff detected!")
-					fmt.Println("The changes are quite extensive, which might affect AI analysis quality.")
-					fmt.Println("Consider using --summarized flag for better results.")
-					fmt.Println("Proceeding with chunked analysis...")
-				}
+				diff, diffErr = getFullDiff(base)
 			}
 
 			if diffErr != nil {
 				return fmt.Errorf("failed to get diff: %w", diffErr)
 			}
 
+			// Keep the content of sensitive files out of the request
+			redactor, err := ai.DefaultRedactor()
+			if err != nil {
+				return err
+			}
+			diff, excluded := redactor.RedactDiff(diff)
+			for _, path := range excluded {
+				fmt.Printf("🔒 Not sending the content of %s to the AI\n", path)
+			}
+
 			// Generate commit message if not provided
 			if message == "" {
+				var truncated bool
 				var genErr error
-				message, genErr = client.GenerateCommitMessage(cmd.Context(), diff)
+				message, truncated, genErr = opts.commitAI(client).GenerateCommitMessage(cmd.Context(), diff)
 				if genErr != nil {
 					return fmt.Errorf("failed to generate commit message: %w", genErr)
 				}
 
-				if len(diff) > 12000 {
-					fmt.Println("\n⚠️  Note: Some changes were too large and had to be summarized.")
-					fmt.Println("The commit message may not reflect all changes in detail.")
-					fmt.Println("Use --summarized flag next time for better handling of large changes.")
+				if truncated {
+					fmt.Println("\n⚠️  Note: Some changes were too large and were only summarized by their line counts.")
+					fmt.Println("The commit message may not reflect them in detail.")
+				}
+
+				warnNewScopes(opts.style, message)
+
+				if !skipReview {
+					message, err = reviewCommitMessage(cmd.Context(), client, opts.commitAI(client), diff, message)
+					if errors.Is(err, errCommitAborted) {
+						fmt.Println("🛑 Commit aborted, your changes are still staged")
+						return nil
+					}
+					if err != nil {
+						return err
+					}
 				}
 			}
 
+			message = opts.finish(message)
+
 			// Create commit
-			if err := createCommit(message); err != nil {
+			var commitArgs []string
+			if amend {
+				commitArgs = append(commitArgs, "--amend")
+			}
+			if err := createCommit(message, commitArgs...); err != nil {
 				return fmt.Errorf("failed to create commit: %w", err)
 			}
 
 			fmt.Printf("\n✨ Committed with message:\n%s\n", message)
-			return nil
+			if len(opts.linked) > 0 {
+				hash, err := headCommit()
+				if err != nil {
+					return fmt.Errorf("failed to read the new commit: %w", err)
+				}
+				recordCommit(opts.linked, hash, message, amended...)
+			}
+			return syncBranch(syncOpts)
 		},
 	}
 
 	cmd.Flags().StringVarP(&message, "message", "m", "", "Use provided commit message instead of generating one")
 	cmd.Flags().BoolVarP(&stageAll, "all", "a", false, "Stage all changes")
 	cmd.Flags().BoolVarP(&summarizedDiff, "summarized", "s", false, "Send only file names and line counts to AI")
+	cmd.Flags().BoolVarP(&skipReview, "yes", "y", false, "Commit the generated message without reviewing it")
+	cmd.Flags().BoolVar(&splitCommits, "split", false, "Split the staged changes into several atomic commits")
+	cmd.Flags().StringSliceVarP(&tasks, "task", "t", nil, "Link the commit to these task IDs, in addition to the detected ones")
+	cmd.Flags().StringArrayVar(&coAuthors, "co-author", nil, "Add a Co-authored-by trailer, as \"Name <email>\"")
+	cmd.Flags().BoolVar(&signOff, "signoff", false, "Add a Signed-off-by trailer for the git user")
+	cmd.Flags().BoolVar(&amend, "amend", false, "Amend the last commit, with a message for it and the staged changes together")
+	cmd.Flags().BoolVar(&syncAfter, "sync", false, "Pull with rebase and push after committing, see yolo sync")
+	cmd.Flags().BoolVar(&force, "force", false, "With --sync, push a rewritten history with --force-with-lease")
 
 	return cmd
 }
 
 func stageAllChanges() error {
-	cmd := exec.Command("git", "add", "-A")
-	return cmd.Run()
+	_, err := git.Run("", "add", "-A")
+	return err
 }
 
-// getFullDiff returns the complete diff of staged changes
-func getFullDiff() (string, error) {
-	cmd := exec.Command("git", "diff", "--cached")
+// getFullDiff returns the complete diff of staged changes, compared to base
+// when it is not "" instead of HEAD
+func getFullDiff(base string) (string, error) {
+	cmd := exec.Command("git", diffCachedArgs(base)...)
 	output, err := cmd.Output()
 	if err != nil {
 		return "", err
@@ -121,9 +253,9 @@ func getFullDiff() (string, error) {
 }
 
 // getSummarizedDiff returns a summary of changes (files and line counts)
-func getSummarizedDiff() (string, error) {
+func getSummarizedDiff(base string) (string, error) {
 	// Get list of changed files
-	cmd := exec.Command("git", "diff", "--cached", "--numstat")
+	cmd := exec.Command("git", diffCachedArgs(base, "--numstat")...)
 	output, err := cmd.Output()
 	if err != nil {
 		return "", err
@@ -155,7 +287,7 @@ func getSummarizedDiff() (string, error) {
 	}
 
 	// Add a summary of the types of files changed
-	cmd = exec.Command("git", "diff", "--cached", "--name-only")
+	cmd = exec.Command("git", diffCachedArgs(base, "--name-only")...)
 	output, err = cmd.Output()
 	if err != nil {
 		return "", err
@@ -180,7 +312,17 @@ func getSummarizedDiff() (string, error) {
 	return summary.String(), nil
 }
 
-func createCommit(message string) error {
-	cmd := exec.Command("git", "commit", "-m", message)
-	return cmd.Run()
+// diffCachedArgs are the arguments of a git diff of the index, compared to
+// base when it is not "" instead of HEAD
+func diffCachedArgs(base string, flags ...string) []string {
+	args := append([]string{"diff", "--cached"}, flags...)
+	if base != "" {
+		args = append(args, base)
+	}
+	return args
+}
+
+func createCommit(message string, args ...string) error {
+	_, err := git.Run("", append(append([]string{"commit"}, args...), "-m", message)...)
+	return err
 }
@@ -0,0 +1,59 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/baudevs/yolo.baudevs.com/internal/commitmsg"
+	"github.com/baudevs/yolo.baudevs.com/internal/git"
+)
+
+// emptyTree is git's hash of the empty tree, the base of a root commit
+const emptyTree = "4b825dc642cb6eb9a060e54bf8d69288fbee4904"
+
+// amendBase returns what an amended HEAD is compared to: its parent, or the
+// empty tree for a root commit
+func amendBase() (string, error) {
+	head, err := git.NewGitOps("").Head()
+	if err != nil {
+		return "", err
+	}
+	if head == "" {
+		return "", fmt.Errorf("there is no commit to amend yet")
+	}
+	parent, err := git.Run("", "rev-parse", "--verify", "-q", "HEAD^")
+	if err != nil {
+		return emptyTree, nil
+	}
+	return strings.TrimSpace(parent), nil
+}
+
+// keptTrailers returns the trailers of messages being rewritten that the new
+// message keeps: issue refs, co-authors and sign-offs. Breaking changes are
+// described again by the new message.
+func keptTrailers(messages ...string) []commitmsg.Footer {
+	var kept []commitmsg.Footer
+	for _, text := range messages {
+		msg, err := commitmsg.Parse(text)
+		if err != nil {
+			continue
+		}
+		for _, f := range msg.Footers {
+			switch strings.ToLower(f.Token) {
+			case "refs", "closes", "fixes", "co-authored-by", "signed-off-by":
+				kept = append(kept, f)
+			}
+		}
+	}
+	return kept
+}
+
+// warnIfPushed points out that rewriting a pushed commit needs a force push
+func warnIfPushed(rev string) {
+	output, err := git.Run("", "branch", "-r", "--contains", rev)
+	if err != nil || strings.TrimSpace(output) == "" {
+		return
+	}
+	fmt.Printf("⚠️  %s is already on %s, pushing the rewritten history will need --force-with-lease\n",
+		rev, strings.Fields(output)[0])
+}
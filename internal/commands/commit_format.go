@@ -0,0 +1,76 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/baudevs/yolo.baudevs.com/internal/ai"
+	"github.com/baudevs/yolo.baudevs.com/internal/commitmsg"
+	"github.com/baudevs/yolo.baudevs.com/internal/config"
+	"github.com/baudevs/yolo.baudevs.com/internal/git"
+	"github.com/baudevs/yolo.baudevs.com/internal/relationships"
+)
+
+// commitOptions is how the messages of a yolo commit run are written
+type commitOptions struct {
+	style     commitmsg.Style
+	formatter commitmsg.Formatter
+	linked    []relationships.WorkItem
+	trailers  []commitmsg.Footer
+}
+
+// commitAI returns a CommitAI writing messages in the project's format,
+// with the linked tasks as issue refs
+func (o commitOptions) commitAI(client *ai.Client) *ai.CommitAI {
+	return ai.NewCommitAI(client).WithFormatter(o.formatter).WithIssueRefs(taskIDs(o.linked)...)
+}
+
+// finish adds the linked tasks' refs and the trailers to a message
+func (o commitOptions) finish(message string) string {
+	if len(o.linked) > 0 {
+		message = commitmsg.AddRefs(message, taskIDs(o.linked)...)
+	}
+	return commitmsg.AddTrailers(message, o.trailers...)
+}
+
+// commitFormatter returns the formatter of the project's commit.format
+func commitFormatter() (commitmsg.Formatter, error) {
+	settings, err := config.LoadProjectSettings()
+	if err != nil {
+		return nil, err
+	}
+	return commitmsg.NewFormatter(settings.Commit.Format, settings.Commit.Template)
+}
+
+// commitTrailers returns the Co-authored-by trailers of the co-authors, and a
+// Signed-off-by trailer for the git user when signing off
+func commitTrailers(coAuthors []string, signOff bool) ([]commitmsg.Footer, error) {
+	var trailers []commitmsg.Footer
+	for _, author := range coAuthors {
+		if !commitmsg.IsIdentity(author) {
+			return nil, fmt.Errorf("co-author %q should be \"Name <email>\"", author)
+		}
+		trailers = append(trailers, commitmsg.Footer{Token: "Co-authored-by", Value: strings.TrimSpace(author)})
+	}
+
+	if !signOff {
+		if settings, err := config.LoadProjectSettings(); err == nil {
+			signOff = settings.Commit.SignOff
+		}
+	}
+	if signOff {
+		name, err := git.Run("", "config", "user.name")
+		if err != nil {
+			return nil, fmt.Errorf("failed to sign off, git user.name is not set: %w", err)
+		}
+		email, err := git.Run("", "config", "user.email")
+		if err != nil {
+			return nil, fmt.Errorf("failed to sign off, git user.email is not set: %w", err)
+		}
+		trailers = append(trailers, commitmsg.Footer{
+			Token: "Signed-off-by",
+			Value: fmt.Sprintf("%s <%s>", strings.TrimSpace(name), strings.TrimSpace(email)),
+		})
+	}
+	return trailers, nil
+}
@@ -0,0 +1,129 @@
+package commands
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/baudevs/yolo.baudevs.com/internal/git"
+	"github.com/baudevs/yolo.baudevs.com/internal/relationships"
+	"github.com/baudevs/yolo.baudevs.com/internal/review"
+)
+
+// linkedTasks finds the tasks a commit is for: those named with --task, in
+// the branch name (e.g. feat/T012-login) and whose files are staged.
+// Detected IDs without a task file are ignored, named ones are an error.
+func linkedTasks(named []string) ([]relationships.WorkItem, error) {
+	tasks, err := relationships.NewManager(nil).LoadWorkItems(relationships.Task)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load tasks: %w", err)
+	}
+	byID := make(map[string]relationships.WorkItem, len(tasks))
+	for _, task := range tasks {
+		byID[task.ID] = task
+	}
+
+	var linked []relationships.WorkItem
+	seen := make(map[string]bool)
+	add := func(id string) bool {
+		id = strings.ToUpper(strings.TrimSpace(id))
+		task, ok := byID[id]
+		if ok && !seen[id] {
+			seen[id] = true
+			linked = append(linked, task)
+		}
+		return ok
+	}
+
+	for _, id := range named {
+		if !add(id) {
+			return nil, fmt.Errorf("task %s not found", id)
+		}
+	}
+
+	// An unborn branch has a name too, so ask symbolic-ref rather than rev-parse
+	branch, _ := git.Run("", "symbolic-ref", "--quiet", "--short", "HEAD")
+	staged, _ := git.Run("", "diff", "--cached", "--name-only", "--", filepath.Join("yolo", "tasks"))
+
+	var detected []string
+	detected = append(detected, review.TaskRefs(branch)...)
+	for _, path := range strings.Fields(staged) {
+		if onlyActivityStaged(path) {
+			continue
+		}
+		detected = append(detected, review.TaskRefs(filepath.Base(path))...)
+	}
+	for _, id := range detected {
+		if strings.HasPrefix(id, "T") {
+			add(id)
+		}
+	}
+
+	return linked, nil
+}
+
+// taskIDs returns the IDs of the tasks
+func taskIDs(tasks []relationships.WorkItem) []string {
+	ids := make([]string, len(tasks))
+	for i, task := range tasks {
+		ids[i] = task.ID
+	}
+	return ids
+}
+
+// activityChange matches the lines recordCommit changes in a task file: its
+// commit entries, the Activity heading, the last updated date and blank lines
+var activityChange = regexp.MustCompile(`^(- \d{4}-\d{2}-\d{2} Commit [0-9a-f]+: .*|## ` +
+	relationships.ActivitySection + `\s*|Last Updated: .*|\s*)$`)
+
+// onlyActivityStaged reports whether the staged changes of a task file are
+// only commits recorded in its activity, which do not make the next commit
+// about the task
+func onlyActivityStaged(path string) bool {
+	diff, err := git.Run("", "diff", "--cached", "--unified=0", "--", path)
+	if err != nil {
+		return false
+	}
+	changed := false
+	for _, line := range strings.Split(diff, "\n") {
+		if strings.HasPrefix(line, "+++") || strings.HasPrefix(line, "---") ||
+			(!strings.HasPrefix(line, "+") && !strings.HasPrefix(line, "-")) {
+			continue
+		}
+		if !activityChange.MatchString(line[1:]) {
+			return false
+		}
+		changed = true
+	}
+	return changed
+}
+
+// recordCommit adds the commit to the activity of the tasks it is linked to.
+// The entry of a commit it replaces, e.g. by an amend or a squash, is updated
+// instead of adding a second one.
+func recordCommit(tasks []relationships.WorkItem, hash, message string, replaced ...string) {
+	subject, _, _ := strings.Cut(strings.TrimSpace(message), "\n")
+	prefixes := make([]string, len(replaced))
+	for i, old := range replaced {
+		prefixes[i] = fmt.Sprintf("Commit %s:", old)
+	}
+
+	relManager := relationships.NewManager(nil)
+	for _, task := range tasks {
+		if err := relManager.ReplaceActivity(task, prefixes, fmt.Sprintf("Commit %s: %s", hash, subject)); err != nil {
+			fmt.Printf("⚠️  Could not record the commit in %s: %v\n", task.ID, err)
+			continue
+		}
+		fmt.Printf("📝 Recorded %s in the activity of %s\n", hash, task.ID)
+	}
+}
+
+// headCommit returns the abbreviated hash of the last commit
+func headCommit() (string, error) {
+	output, err := git.Run("", "rev-parse", "--short", "HEAD")
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(output), nil
+}
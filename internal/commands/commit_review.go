@@ -0,0 +1,132 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/baudevs/yolo.baudevs.com/internal/ai"
+)
+
+// errCommitAborted is returned when the author aborts the commit review
+var errCommitAborted = errors.New("commit aborted")
+
+// reviewCommitMessage shows a generated message and lets the author accept it,
+// edit it in $EDITOR, regenerate it (optionally with a hint) or abort
+func reviewCommitMessage(ctx context.Context, client *ai.Client, commitAI *ai.CommitAI, diff, message string) (string, error) {
+	for {
+		fmt.Println("\n📝 Commit message:")
+		fmt.Println(strings.Repeat("─", 60))
+		fmt.Println(message)
+		fmt.Println(strings.Repeat("─", 60))
+		fmt.Print("[a]ccept, [e]dit, [r]egenerate [hint], [q]uit (default: accept): ")
+
+		answer, err := stdinReader.ReadString('\n')
+		if err != nil && answer == "" {
+			fmt.Println()
+			return "", fmt.Errorf("no answer to the commit review; use --yes to commit without it")
+		}
+		action, hint, _ := strings.Cut(strings.TrimSpace(answer), " ")
+
+		switch strings.ToLower(action) {
+		case "", "a", "accept", "y", "yes":
+			return message, nil
+
+		case "e", "edit":
+			edited, err := editCommitMessage(message)
+			if err != nil {
+				return "", err
+			}
+			if edited == "" {
+				fmt.Println("⚠️  The edited message is empty, keeping the previous one")
+				continue
+			}
+			message = edited
+
+		case "r", "regenerate":
+			hint = strings.TrimSpace(hint)
+			if hint == "" {
+				fmt.Print("Hint for the AI (optional, e.g. \"focus on the parser change\"): ")
+				hint, _ = stdinReader.ReadString('\n')
+				hint = strings.TrimSpace(hint)
+			}
+
+			fmt.Println("🤖 Regenerating commit message...")
+			if hint == "" {
+				regenerated, _, err := commitAI.GenerateCommitMessage(ctx, diff)
+				if err != nil {
+					return "", fmt.Errorf("failed to regenerate commit message: %w", err)
+				}
+				message = regenerated
+				continue
+			}
+
+			revised, err := client.ReviseCommitMessage(ctx, diff, message, hint)
+			if err != nil {
+				return "", fmt.Errorf("failed to regenerate commit message: %w", err)
+			}
+			if message, err = commitAI.ReformatCommitMessage(revised); err != nil {
+				return "", err
+			}
+
+		case "q", "quit", "abort", "n", "no":
+			return "", errCommitAborted
+
+		default:
+			fmt.Printf("Unknown choice %q\n", action)
+		}
+	}
+}
+
+// editCommitMessage opens the message in $EDITOR and returns it without
+// comment lines, like git does
+func editCommitMessage(message string) (string, error) {
+	file, err := os.CreateTemp("", "yolo-commit-*.txt")
+	if err != nil {
+		return "", fmt.Errorf("failed to create message file: %w", err)
+	}
+	defer os.Remove(file.Name())
+
+	content := message + "\n\n# Edit the commit message above. Lines starting with '#' are ignored,\n# and an empty message keeps the previous one.\n"
+	if _, err := file.WriteString(content); err != nil {
+		file.Close()
+		return "", fmt.Errorf("failed to write message file: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return "", fmt.Errorf("failed to write message file: %w", err)
+	}
+
+	if err := runEditor(file.Name()); err != nil {
+		return "", fmt.Errorf("failed to run editor: %w", err)
+	}
+
+	data, err := os.ReadFile(file.Name())
+	if err != nil {
+		return "", fmt.Errorf("failed to read message file: %w", err)
+	}
+
+	var lines []string
+	for _, line := range strings.Split(string(data), "\n") {
+		if !strings.HasPrefix(line, "#") {
+			lines = append(lines, strings.TrimRight(line, " \t\r"))
+		}
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n")), nil
+}
+
+// runEditor opens a file in $EDITOR, which may include arguments (e.g. "code --wait")
+func runEditor(file string) error {
+	editor := strings.Fields(os.Getenv("EDITOR"))
+	if len(editor) == 0 {
+		editor = []string{"vim"}
+	}
+
+	cmd := exec.Command(editor[0], append(editor[1:], file)...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
@@ -0,0 +1,99 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/baudevs/yolo.baudevs.com/internal/ai"
+	"github.com/baudevs/yolo.baudevs.com/internal/git"
+	"github.com/baudevs/yolo.baudevs.com/internal/split"
+)
+
+// runSplitCommit proposes atomic commits for the staged hunks and, once
+// confirmed, creates them one after the other
+func runSplitCommit(ctx context.Context, client *ai.Client, opts commitOptions, skipPrompt bool) error {
+	g := git.NewGitOps("")
+	files, err := g.Diff("--cached", "--binary", "--no-renames")
+	if err != nil {
+		return fmt.Errorf("failed to get diff: %w", err)
+	}
+
+	units := split.Units(files)
+	if len(units) == 0 {
+		return fmt.Errorf("nothing staged to commit")
+	}
+
+	// Keep the content of sensitive files out of the request
+	redactor, err := ai.DefaultRedactor()
+	if err != nil {
+		return err
+	}
+	for i := range units {
+		if redactor.ExcludesPath(units[i].File.Path) {
+			units[i].Hide = true
+		}
+	}
+
+	fmt.Printf("✂️  Grouping %d staged hunks into commits...\n", len(units))
+	commitAI := opts.commitAI(client)
+	groups, err := split.Propose(ctx, client, commitAI, units)
+	if err != nil {
+		return err
+	}
+
+	for i := range groups {
+		message := groups[i].Message
+		if !groups[i].Formatted {
+			if message, err = commitAI.ReformatCommitMessage(message); err != nil {
+				return err
+			}
+		}
+		groups[i].Message = opts.finish(message)
+	}
+
+	fmt.Printf("\n📦 Proposed %d commits:\n", len(groups))
+	for i, group := range groups {
+		subject, body, _ := strings.Cut(group.Message, "\n")
+		fmt.Printf("\n%d. %s\n", i+1, subject)
+		if body = strings.TrimSpace(body); body != "" {
+			fmt.Println(indent(body, "   "))
+		}
+		for _, u := range group.Units {
+			added, deleted := u.Stats()
+			fmt.Printf("   - %s (+%d -%d)\n", u, added, deleted)
+		}
+		warnNewScopes(opts.style, group.Message)
+	}
+	fmt.Println()
+
+	if !skipPrompt {
+		ok, err := confirm(fmt.Sprintf("Create these %d commits?", len(groups)))
+		if err != nil {
+			return err
+		}
+		if !ok {
+			fmt.Println("🛑 Nothing committed, your changes are still staged")
+			return errCommitAborted
+		}
+	}
+
+	var hashes []string
+	backup, err := split.Apply(g, groups, func(i int, hash string) {
+		hashes = append(hashes, hash)
+		subject, _, _ := strings.Cut(groups[i].Message, "\n")
+		fmt.Printf("✅ %s %s\n", hash, subject)
+	})
+	if err != nil {
+		fmt.Println("🛑 The split was undone and your changes are staged as before")
+		fmt.Printf("   Staged index backup: %s\n", backup.Tree)
+		return err
+	}
+
+	for i, hash := range hashes {
+		recordCommit(opts.linked, hash, groups[i].Message)
+	}
+
+	fmt.Printf("\n✨ Created %d commits\n", len(groups))
+	return nil
+}
@@ -0,0 +1,78 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/baudevs/yolo.baudevs.com/internal/ai"
+	"github.com/baudevs/yolo.baudevs.com/internal/commitmsg"
+	"github.com/spf13/cobra"
+)
+
+// applyCommitStyle has the commit prompts follow the repository's commit style
+func applyCommitStyle() commitmsg.Style {
+	style, err := commitmsg.LoadStyle(false)
+	if err != nil {
+		fmt.Printf("⚠️  Could not learn the commit style: %v\n", err)
+	}
+	ai.SetCommitStyle(style.Conventions(), style.ExampleList())
+	return style
+}
+
+// warnNewScopes points out the scopes of a message the repository never used
+func warnNewScopes(style commitmsg.Style, message string) {
+	if style.Conventional == 0 {
+		return
+	}
+	msg, err := commitmsg.Parse(message)
+	if err != nil {
+		return
+	}
+	for _, scope := range strings.Split(msg.Scope, ",") {
+		if scope = strings.TrimSpace(scope); scope == "" || style.HasScope(scope) {
+			continue
+		}
+		if len(style.Scopes) == 0 {
+			fmt.Printf("⚠️  This repository does not use scopes, %q would be the first one\n", scope)
+			continue
+		}
+		fmt.Printf("⚠️  Scope %q has never been used in this repository\n", scope)
+	}
+}
+
+func newAIStyleCommand() *cobra.Command {
+	var refresh bool
+
+	cmd := &cobra.Command{
+		Use:   "style",
+		Short: "Show the commit style learned from the git history",
+		Long: `Show the commit conventions learned from the last commits of the repository:
+types, scopes, subject style and trailers, with example subjects. They are
+added to every commit prompt so generated messages match the history.
+
+The style is cached in yolo/settings/commit_style.yml and learned again after
+a week, or with --refresh.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			style, err := commitmsg.LoadStyle(refresh)
+			if err != nil {
+				return err
+			}
+			if style.Commits == 0 {
+				fmt.Println("No commits to learn a style from yet")
+				return nil
+			}
+
+			fmt.Printf("📐 Commit style learned from %d commits on %s\n\n", style.Commits, style.LearnedAt.Format("2006-01-02"))
+			fmt.Println(style.Conventions())
+			if examples := style.ExampleList(); examples != "" {
+				fmt.Printf("\nExamples:\n%s\n", examples)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&refresh, "refresh", false, "Learn the style again from the current history")
+
+	return cmd
+}
@@ -0,0 +1,39 @@
+package commands
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/baudevs/yolo.baudevs.com/internal/ai"
+)
+
+// stdinReader is shared so consecutive prompts don't lose buffered input
+var stdinReader = bufio.NewReader(os.Stdin)
+
+// confirm asks a yes/no question, defaulting to no.
+// It returns an error when no answer can be read, e.g. stdin is not a terminal.
+func confirm(question string) (bool, error) {
+	fmt.Printf("%s [y/N] ", question)
+
+	answer, err := stdinReader.ReadString('\n')
+	if err != nil && answer == "" {
+		fmt.Println()
+		return false, fmt.Errorf("no answer to %q", question)
+	}
+
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes", nil
+}
+
+// PreviewPayload prints a redacted AI request and asks whether to send it
+func PreviewPayload(payload string, redactions []ai.Redaction) bool {
+	fmt.Println("\n📤 Payload for the AI (" + formatRedactions(redactions) + "):")
+	fmt.Println(strings.Repeat("─", 60))
+	fmt.Println(payload)
+	fmt.Println(strings.Repeat("─", 60))
+
+	ok, err := confirm("Send it?")
+	return err == nil && ok
+}
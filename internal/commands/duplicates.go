@@ -0,0 +1,36 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/baudevs/yolo.baudevs.com/internal/relationships"
+)
+
+// checkDuplicates warns about existing items similar to the one being created
+// and asks whether to go on, unless skipPrompt is set
+func checkDuplicates(itemType relationships.WorkItemType, description string, items []relationships.WorkItem, skipPrompt bool) error {
+	duplicates := relationships.FindDuplicates(itemType, description, items)
+	if len(duplicates) == 0 {
+		return nil
+	}
+
+	kind := strings.ToLower(string(itemType))
+	fmt.Printf("⚠️  This %s looks similar to:\n", kind)
+	for _, match := range duplicates {
+		fmt.Printf("   [%s] %s (%.0f%% similar, %s)\n", match.Item.ID, match.Item.Title, match.Score*100, match.Item.Path)
+	}
+
+	if skipPrompt {
+		return nil
+	}
+
+	ok, err := confirm(fmt.Sprintf("Create the %s anyway?", kind))
+	if err != nil {
+		return fmt.Errorf("possible duplicate %s, rerun with --yes to create it anyway", kind)
+	}
+	if !ok {
+		return fmt.Errorf("cancelled, possible duplicate of [%s]", duplicates[0].Item.ID)
+	}
+	return nil
+}
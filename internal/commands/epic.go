@@ -2,10 +2,12 @@ package commands
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/baudevs/yolo.baudevs.com/internal/ai"
@@ -28,6 +30,11 @@ An epic is a large body of work that can be broken down into features and tasks.
 3. Create implementation tasks for each feature
 4. Link everything together automatically
 
+Features and tasks are generated in parallel, with at most --concurrency
+requests at a time. IDs are still allocated in order once everything is
+generated. If a feature fails, the others are stopped, the features that
+were complete are saved and the failures are listed.
+
 Examples:
   yolo epic "Build user authentication system"
   yolo epic "Create analytics dashboard"
@@ -37,12 +44,29 @@ Examples:
 	}
 
 	cmd.Flags().StringP("status", "s", "planning", "Epic status (planning, in-progress, done)")
+	cmd.Flags().BoolP("yes", "y", false, "Create the epic even if similar epics exist")
+	cmd.Flags().IntP("concurrency", "j", 0, "Maximum parallel AI requests (default: ai.concurrency from the config, or 4)")
+	addLanguageFlag(cmd)
 
 	return cmd
 }
 
+// epicFeature is a generated feature of an epic, not yet written to disk
+type epicFeature struct {
+	Title   string
+	Content string
+	Tasks   []epicTask
+}
+
+// epicTask is a generated task of a feature, not yet written to disk
+type epicTask struct {
+	Title   string
+	Content string
+}
+
 func runEpic(cmd *cobra.Command, args []string) error {
 	description := args[0]
+	ctx := cmd.Context()
 	fmt.Println("🌟 Creating your epic with AI...")
 
 	// Load config
@@ -66,26 +90,295 @@ func runEpic(cmd *cobra.Command, args []string) error {
 	// Create relationship manager
 	relManager := relationships.NewManager(client)
 
-	// Generate epic content with AI
-	epicPrompt := fmt.Sprintf(`Create a comprehensive epic description for:
-"%s"
-
-The description should include:
-1. Strategic goals and objectives
-2. Business value and impact
-3. High-level technical considerations
-4. Success criteria and metrics
+	// Warn before creating a near-duplicate
+	items, err := relManager.LoadWorkItems(relationships.Epic)
+	if err != nil {
+		return fmt.Errorf("failed to load work items: %w", err)
+	}
+	skipPrompt, _ := cmd.Flags().GetBool("yes")
+	if err := checkDuplicates(relationships.Epic, description, items, skipPrompt); err != nil {
+		return err
+	}
 
-Make it detailed but concise.`, description)
+	// Generate epic content with AI
+	epicPrompt, err := ai.RenderPrompt("epic.description", ai.PromptData{"Description": description})
+	if err != nil {
+		return err
+	}
 
 	fmt.Println("🤖 Generating epic description...")
-	epicContent, err := client.Ask(context.Background(), epicPrompt)
+	epicContent, err := client.Ask(ctx, epicPrompt)
 	if err != nil {
 		return fmt.Errorf("failed to generate epic content: %w", err)
 	}
 
-	// Create epic file
+	fmt.Println("🤖 Generating implementation features...")
+	featureTitles, err := relManager.SuggestChildren(ctx, relationships.Epic, description)
+	if err != nil {
+		return fmt.Errorf("failed to generate features: %w", err)
+	}
+
+	// Generate every feature and its tasks in parallel
+	concurrency, _ := cmd.Flags().GetInt("concurrency")
+	if concurrency <= 0 {
+		concurrency = cfg.AI.Concurrency
+	}
+	if concurrency <= 0 {
+		concurrency = config.DefaultConcurrency
+	}
+
+	fmt.Printf("🤖 Generating %d features and their tasks (%d at a time)...\n", len(featureTitles), concurrency)
+	gen := &epicGenerator{
+		client:     client,
+		relManager: relManager,
+		epic:       description,
+		slots:      make(chan struct{}, concurrency),
+		progress:   newEpicProgress(len(featureTitles)),
+	}
+	generated := make([]epicFeature, len(featureTitles))
+	errs := utils.ForEach(ctx, len(featureTitles), concurrency, func(ctx context.Context, i int) error {
+		feature, err := gen.feature(ctx, featureTitles[i])
+		if err != nil {
+			return fmt.Errorf("feature %q: %w", featureTitles[i], err)
+		}
+		generated[i] = feature
+		return nil
+	})
+	gen.progress.done()
+
+	// Write the epic, then the complete features and their tasks in order,
+	// so IDs are allocated the same way whatever finished first
 	status, _ := cmd.Flags().GetString("status")
+	currentEpic, err := writeEpic(description, status, epicContent)
+	if err != nil {
+		return err
+	}
+
+	var features []relationships.WorkItem
+	var allTasks []relationships.WorkItem
+	var failed []error
+	for i, feature := range generated {
+		if errs[i] != nil {
+			if !errors.Is(errs[i], context.Canceled) {
+				failed = append(failed, errs[i])
+			}
+			continue
+		}
+
+		featureItem, err := writeEpicFeature(currentEpic, feature)
+		if err != nil {
+			return err
+		}
+		features = append(features, featureItem)
+		fmt.Printf("✅ Created feature: %s - %s\n", featureItem.ID, featureItem.Title)
+
+		var tasks []relationships.WorkItem
+		for _, task := range feature.Tasks {
+			taskItem, err := writeEpicTask(currentEpic, featureItem, task)
+			if err != nil {
+				return err
+			}
+			tasks = append(tasks, taskItem)
+			fmt.Printf("   ✅ Created task: %s - %s\n", taskItem.ID, taskItem.Title)
+		}
+		allTasks = append(allTasks, tasks...)
+
+		// Update feature relationships
+		featureRelations := map[relationships.WorkItemType][]relationships.WorkItem{
+			relationships.Epic: {currentEpic},
+			relationships.Task: tasks,
+		}
+		if err := relManager.UpdateRelationships(featureItem.Path, featureRelations); err != nil {
+			return fmt.Errorf("failed to update feature relationships: %w", err)
+		}
+	}
+
+	// Update epic relationships
+	epicRelations := map[relationships.WorkItemType][]relationships.WorkItem{
+		relationships.Feature: features,
+		relationships.Task:    allTasks,
+	}
+	if err := relManager.UpdateRelationships(currentEpic.Path, epicRelations); err != nil {
+		return fmt.Errorf("failed to update epic relationships: %w", err)
+	}
+
+	if err := utils.FirstError(errs); err != nil {
+		fmt.Printf("\n⚠️  Epic %s was created with %d of %d features and %d tasks\n",
+			currentEpic.ID, len(features), len(featureTitles), len(allTasks))
+		for _, err := range failed {
+			fmt.Printf("  ❌ %v\n", err)
+		}
+		if skipped := len(featureTitles) - len(features) - len(failed); skipped > 0 {
+			fmt.Printf("  ⏹  %d features were stopped after the failure\n", skipped)
+		}
+		fmt.Println("\nAdd the missing features with 'yolo feature' once the problem is fixed.")
+		return fmt.Errorf("failed to generate %d of %d features: %w", len(featureTitles)-len(features), len(featureTitles), err)
+	}
+
+	fmt.Printf("\n✨ Epic %s created successfully!\n", currentEpic.ID)
+	fmt.Printf("📋 Created %d features and %d tasks\n", len(features), len(allTasks))
+	fmt.Println("\nNext steps:")
+	fmt.Println("1. Review the generated content")
+	fmt.Println("2. Assign features and tasks to team members")
+	fmt.Println("3. See your progress in 3D with 'yolo graph'")
+
+	return nil
+}
+
+// epicGenerator generates the features of an epic concurrently. Every AI
+// request takes one of the slots, so at most cap(slots) run at once however
+// the work is split between features and tasks.
+type epicGenerator struct {
+	client     *ai.Client
+	relManager *relationships.RelationshipManager
+	epic       string
+	slots      chan struct{}
+	progress   *epicProgress
+}
+
+// limit runs fn once a request slot is free
+func (g *epicGenerator) limit(ctx context.Context, fn func() error) error {
+	select {
+	case g.slots <- struct{}{}:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	defer func() { <-g.slots }()
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return fn()
+}
+
+// ask sends a prompt once a request slot is free
+func (g *epicGenerator) ask(ctx context.Context, prompt string) (string, error) {
+	var answer string
+	err := g.limit(ctx, func() error {
+		var err error
+		answer, err = g.client.Ask(ctx, prompt)
+		return err
+	})
+	return answer, err
+}
+
+// feature generates a feature's description and tasks, the tasks in parallel
+func (g *epicGenerator) feature(ctx context.Context, title string) (epicFeature, error) {
+	feature := epicFeature{Title: title}
+
+	featurePrompt, err := ai.RenderPrompt("epic.feature", ai.PromptData{
+		"Title": title,
+		"Epic":  g.epic,
+	})
+	if err != nil {
+		return feature, err
+	}
+	feature.Content, err = g.ask(ctx, featurePrompt)
+	if err != nil {
+		return feature, fmt.Errorf("failed to generate feature content: %w", err)
+	}
+
+	var taskTitles []string
+	err = g.limit(ctx, func() error {
+		var err error
+		taskTitles, err = g.relManager.SuggestChildren(ctx, relationships.Feature, title)
+		return err
+	})
+	if err != nil {
+		return feature, fmt.Errorf("failed to generate tasks: %w", err)
+	}
+	g.progress.addTasks(len(taskTitles))
+
+	feature.Tasks = make([]epicTask, len(taskTitles))
+	errs := utils.ForEach(ctx, len(taskTitles), cap(g.slots), func(ctx context.Context, i int) error {
+		taskPrompt, err := ai.RenderPrompt("task.child", ai.PromptData{
+			"Title":   taskTitles[i],
+			"Feature": title,
+		})
+		if err != nil {
+			return err
+		}
+		content, err := g.ask(ctx, taskPrompt)
+		if err != nil {
+			return fmt.Errorf("failed to generate task content for %q: %w", taskTitles[i], err)
+		}
+		feature.Tasks[i] = epicTask{Title: taskTitles[i], Content: content}
+		g.progress.completeTask()
+		return nil
+	})
+	if err := utils.FirstError(errs); err != nil {
+		return feature, err
+	}
+
+	g.progress.completeFeature(title)
+	return feature, nil
+}
+
+// epicProgress shows how many features and tasks have been generated. On a
+// terminal it keeps one line up to date; otherwise it prints a line per feature.
+type epicProgress struct {
+	mu           sync.Mutex
+	live         bool
+	features     int
+	tasks        int
+	featuresDone int
+	tasksDone    int
+}
+
+func newEpicProgress(features int) *epicProgress {
+	p := &epicProgress{features: features}
+	if info, err := os.Stdout.Stat(); err == nil && info.Mode()&os.ModeCharDevice != 0 {
+		p.live = true
+	}
+	p.mu.Lock()
+	p.render()
+	p.mu.Unlock()
+	return p
+}
+
+func (p *epicProgress) addTasks(n int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.tasks += n
+	p.render()
+}
+
+func (p *epicProgress) completeTask() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.tasksDone++
+	p.render()
+}
+
+func (p *epicProgress) completeFeature(title string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.featuresDone++
+	if !p.live {
+		fmt.Printf("  ✓ %s (features %d/%d, tasks %d/%d)\n", title, p.featuresDone, p.features, p.tasksDone, p.tasks)
+		return
+	}
+	p.render()
+}
+
+// done ends the live progress line
+func (p *epicProgress) done() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.live {
+		fmt.Println()
+	}
+}
+
+// render redraws the live progress line; callers hold mu
+func (p *epicProgress) render() {
+	if p.live {
+		fmt.Printf("\r⏳ Features %d/%d · Tasks %d/%d ", p.featuresDone, p.features, p.tasksDone, p.tasks)
+	}
+}
+
+// writeEpic writes a new epic file
+func writeEpic(description, status, epicContent string) (relationships.WorkItem, error) {
 	epicID := utils.GenerateID("E")
 	epicPath := filepath.Join("yolo", "epics", fmt.Sprintf("%s.md", epicID))
 
@@ -113,14 +406,14 @@ Last Updated: %s
 		strings.TrimSpace(epicContent))
 
 	if err := os.MkdirAll(filepath.Dir(epicPath), 0755); err != nil {
-		return fmt.Errorf("failed to create epics directory: %w", err)
+		return relationships.WorkItem{}, fmt.Errorf("failed to create epics directory: %w", err)
 	}
 
 	if err := os.WriteFile(epicPath, []byte(epicFileContent), 0644); err != nil {
-		return fmt.Errorf("failed to write epic file: %w", err)
+		return relationships.WorkItem{}, fmt.Errorf("failed to write epic file: %w", err)
 	}
 
-	currentEpic := relationships.WorkItem{
+	return relationships.WorkItem{
 		Type:        relationships.Epic,
 		ID:          epicID,
 		Title:       description,
@@ -128,40 +421,15 @@ Last Updated: %s
 		Status:      status,
 		Path:        epicPath,
 		Content:     epicFileContent,
-	}
-
-	// Generate and create features
-	fmt.Println("🤖 Generating implementation features...")
-	featureTitles, err := relManager.SuggestChildren(context.Background(), relationships.Epic, description)
-	if err != nil {
-		return fmt.Errorf("failed to generate features: %w", err)
-	}
-
-	var features []relationships.WorkItem
-	var allTasks []relationships.WorkItem
-
-	for _, featureTitle := range featureTitles {
-		featurePrompt := fmt.Sprintf(`Create a detailed feature description for:
-"%s"
-
-This feature is part of the epic:
-"%s"
-
-The description should include:
-1. Specific functionality to be implemented
-2. User value and benefits
-3. Technical considerations
-4. Success criteria`, featureTitle, description)
-
-		featureContent, err := client.Ask(context.Background(), featurePrompt)
-		if err != nil {
-			return fmt.Errorf("failed to generate feature content: %w", err)
-		}
+	}, nil
+}
 
-		featureID := utils.GenerateID("F")
-		featurePath := filepath.Join("yolo", "features", fmt.Sprintf("%s.md", featureID))
+// writeEpicFeature writes a generated feature of an epic
+func writeEpicFeature(epic relationships.WorkItem, feature epicFeature) (relationships.WorkItem, error) {
+	featureID := utils.GenerateID("F")
+	featurePath := filepath.Join("yolo", "features", fmt.Sprintf("%s.md", featureID))
 
-		featureFileContent := fmt.Sprintf(`# [%s] %s
+	featureFileContent := fmt.Sprintf(`# [%s] %s
 
 ## Status: planning
 Created: %s
@@ -181,59 +449,38 @@ Epic: [%s] %s
 <!-- YOLO-LINKS-START -->
 - Parent Epic: [%s] %s
 <!-- YOLO-LINKS-END -->
-`, featureID, featureTitle,
-			time.Now().Format("2006-01-02"),
-			time.Now().Format("2006-01-02"),
-			epicID, description,
-			strings.TrimSpace(featureContent),
-			epicID, description)
-
-		if err := os.MkdirAll(filepath.Dir(featurePath), 0755); err != nil {
-			return fmt.Errorf("failed to create features directory: %w", err)
-		}
-
-		if err := os.WriteFile(featurePath, []byte(featureFileContent), 0644); err != nil {
-			return fmt.Errorf("failed to write feature file: %w", err)
-		}
-
-		feature := relationships.WorkItem{
-			Type:        relationships.Feature,
-			ID:          featureID,
-			Title:       featureTitle,
-			Description: featureContent,
-			Status:      "planning",
-			Path:        featurePath,
-			Content:     featureFileContent,
-		}
-		features = append(features, feature)
-
-		fmt.Printf("✅ Created feature: %s - %s\n", featureID, featureTitle)
-
-		// Generate tasks for this feature
-		fmt.Printf("🤖 Generating tasks for feature: %s\n", featureTitle)
-		taskTitles, err := relManager.SuggestChildren(context.Background(), relationships.Feature, featureTitle)
-		if err != nil {
-			return fmt.Errorf("failed to generate tasks: %w", err)
-		}
-
-		for _, taskTitle := range taskTitles {
-			taskPrompt := fmt.Sprintf(`Create a detailed task description for:
-"%s"
+`, featureID, feature.Title,
+		time.Now().Format("2006-01-02"),
+		time.Now().Format("2006-01-02"),
+		epic.ID, epic.Title,
+		strings.TrimSpace(feature.Content),
+		epic.ID, epic.Title)
 
-This task is part of the feature:
-"%s"
+	if err := os.MkdirAll(filepath.Dir(featurePath), 0755); err != nil {
+		return relationships.WorkItem{}, fmt.Errorf("failed to create features directory: %w", err)
+	}
 
-The description should be specific, actionable, and include clear success criteria.`, taskTitle, featureTitle)
+	if err := os.WriteFile(featurePath, []byte(featureFileContent), 0644); err != nil {
+		return relationships.WorkItem{}, fmt.Errorf("failed to write feature file: %w", err)
+	}
 
-			taskContent, err := client.Ask(context.Background(), taskPrompt)
-			if err != nil {
-				return fmt.Errorf("failed to generate task content: %w", err)
-			}
+	return relationships.WorkItem{
+		Type:        relationships.Feature,
+		ID:          featureID,
+		Title:       feature.Title,
+		Description: feature.Content,
+		Status:      "planning",
+		Path:        featurePath,
+		Content:     featureFileContent,
+	}, nil
+}
 
-			taskID := utils.GenerateID("T")
-			taskPath := filepath.Join("yolo", "tasks", fmt.Sprintf("%s.md", taskID))
+// writeEpicTask writes a generated task of a feature
+func writeEpicTask(epic, feature relationships.WorkItem, task epicTask) (relationships.WorkItem, error) {
+	taskID := utils.GenerateID("T")
+	taskPath := filepath.Join("yolo", "tasks", fmt.Sprintf("%s.md", taskID))
 
-			taskFileContent := fmt.Sprintf(`# [%s] %s
+	taskFileContent := fmt.Sprintf(`# [%s] %s
 
 ## Status: planning
 Created: %s
@@ -255,62 +502,30 @@ Epic: [%s] %s
 - Parent Feature: [%s] %s
 - Parent Epic: [%s] %s
 <!-- YOLO-LINKS-END -->
-`, taskID, taskTitle,
-				time.Now().Format("2006-01-02"),
-				time.Now().Format("2006-01-02"),
-				featureID, featureTitle,
-				epicID, description,
-				strings.TrimSpace(taskContent),
-				featureID, featureTitle,
-				epicID, description)
-
-			if err := os.MkdirAll(filepath.Dir(taskPath), 0755); err != nil {
-				return fmt.Errorf("failed to create tasks directory: %w", err)
-			}
-
-			if err := os.WriteFile(taskPath, []byte(taskFileContent), 0644); err != nil {
-				return fmt.Errorf("failed to write task file: %w", err)
-			}
-
-			task := relationships.WorkItem{
-				Type:        relationships.Task,
-				ID:          taskID,
-				Title:       taskTitle,
-				Description: taskContent,
-				Status:      "planning",
-				Path:        taskPath,
-				Content:     taskFileContent,
-			}
-			allTasks = append(allTasks, task)
-
-			fmt.Printf("✅ Created task: %s - %s\n", taskID, taskTitle)
-		}
-
-		// Update feature relationships
-		featureRelations := map[relationships.WorkItemType][]relationships.WorkItem{
-			relationships.Epic: {currentEpic},
-			relationships.Task: allTasks,
-		}
-		if err := relManager.UpdateRelationships(featurePath, featureRelations); err != nil {
-			return fmt.Errorf("failed to update feature relationships: %w", err)
-		}
+`, taskID, task.Title,
+		time.Now().Format("2006-01-02"),
+		time.Now().Format("2006-01-02"),
+		feature.ID, feature.Title,
+		epic.ID, epic.Title,
+		strings.TrimSpace(task.Content),
+		feature.ID, feature.Title,
+		epic.ID, epic.Title)
+
+	if err := os.MkdirAll(filepath.Dir(taskPath), 0755); err != nil {
+		return relationships.WorkItem{}, fmt.Errorf("failed to create tasks directory: %w", err)
 	}
 
-	// Update epic relationships
-	epicRelations := map[relationships.WorkItemType][]relationships.WorkItem{
-		relationships.Feature: features,
-		relationships.Task:    allTasks,
-	}
-	if err := relManager.UpdateRelationships(epicPath, epicRelations); err != nil {
-		return fmt.Errorf("failed to update epic relationships: %w", err)
+	if err := os.WriteFile(taskPath, []byte(taskFileContent), 0644); err != nil {
+		return relationships.WorkItem{}, fmt.Errorf("failed to write task file: %w", err)
 	}
 
-	fmt.Printf("\n✨ Epic %s created successfully!\n", epicID)
-	fmt.Printf("📋 Created %d features and %d tasks\n", len(features), len(allTasks))
-	fmt.Println("\nNext steps:")
-	fmt.Println("1. Review the generated content")
-	fmt.Println("2. Assign features and tasks to team members")
-	fmt.Println("3. See your progress in 3D with 'yolo graph'")
-
-	return nil
+	return relationships.WorkItem{
+		Type:        relationships.Task,
+		ID:          taskID,
+		Title:       task.Title,
+		Description: task.Content,
+		Status:      "planning",
+		Path:        taskPath,
+		Content:     taskFileContent,
+	}, nil
 }
@@ -0,0 +1,115 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/baudevs/yolo.baudevs.com/internal/ai"
+	"github.com/baudevs/yolo.baudevs.com/internal/config"
+	"github.com/baudevs/yolo.baudevs.com/internal/git"
+	"github.com/baudevs/yolo.baudevs.com/internal/license"
+	"github.com/baudevs/yolo.baudevs.com/internal/logging"
+	"github.com/spf13/cobra"
+)
+
+// maxErrorInput bounds the log sent for analysis; the end of a log explains it best
+const maxErrorInput = 8000
+
+// NewExplainErrorCommand returns a new explain-error command
+func NewExplainErrorCommand() *cobra.Command {
+	var contextStr string
+
+	cmd := &cobra.Command{
+		Use:   "explain-error",
+		Short: "Explain an error log or stack trace read from stdin",
+		Long: `Explain an error with AI: what the problem is, why it happened and how to fix it.
+
+The log or stack trace is read from stdin. Only its last part is sent when it is long.
+
+Examples:
+  go test ./... 2>&1 | yolo explain-error
+  yolo explain-error --context "deploying with docker compose" < error.log`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if info, err := os.Stdin.Stat(); err == nil && info.Mode()&os.ModeCharDevice != 0 {
+				fmt.Println("Paste the error, then press Ctrl-D:")
+			}
+
+			input, err := io.ReadAll(os.Stdin)
+			if err != nil {
+				return fmt.Errorf("failed to read stdin: %w", err)
+			}
+			text := strings.TrimSpace(string(input))
+			if text == "" {
+				return fmt.Errorf("no error to explain on stdin")
+			}
+			if len(text) > maxErrorInput {
+				text = "...\n" + text[len(text)-maxErrorInput:]
+			}
+
+			if contextStr == "" {
+				contextStr = "Output pasted by the user"
+			}
+			return explainError(errors.New(text), contextStr)
+		},
+	}
+
+	cmd.Flags().StringVarP(&contextStr, "context", "c", "", "What you were doing when the error happened")
+
+	return cmd
+}
+
+// ExplainFailure shows an AI analysis of a failed git command, when asked
+// for with the flag or the ai.explain_errors config setting
+func ExplainFailure(err error, requested bool) {
+	var gitErr *git.CommandError
+	if !errors.As(err, &gitErr) {
+		return
+	}
+
+	if !requested {
+		cfg, cfgErr := config.LoadConfig()
+		if cfgErr != nil || !cfg.AI.ExplainErrors {
+			return
+		}
+	}
+
+	fmt.Println("\n🔎 Analyzing the error...")
+	contextStr := fmt.Sprintf("YOLO ran `%s`, which printed:\n%s", gitErr.Command(), gitErr.Output)
+	if analysisErr := explainError(err, contextStr); analysisErr != nil {
+		logging.Warn("failed to explain the error", "error", analysisErr)
+	}
+}
+
+// explainError asks the AI to analyze an error and prints the analysis
+func explainError(err error, contextStr string) error {
+	// Load config
+	cfg, cfgErr := config.LoadConfig()
+	if cfgErr != nil {
+		return fmt.Errorf("failed to load config: %w", cfgErr)
+	}
+
+	// Create license manager
+	licenseManager, lmErr := license.NewManager()
+	if lmErr != nil {
+		return fmt.Errorf("failed to create license manager: %w", lmErr)
+	}
+
+	// Create AI client
+	client, clientErr := ai.NewClient(cfg, licenseManager)
+	if clientErr != nil {
+		return fmt.Errorf("failed to create AI client: %w", clientErr)
+	}
+
+	analyzer := ai.NewErrorAnalyzerWithProvider(client.Provider()).WithModel(client.Model())
+	analysis, analysisErr := analyzer.AnalyzeError(err, contextStr)
+	if analysisErr != nil {
+		return analysisErr
+	}
+
+	fmt.Printf("\n%s", analyzer.FormatAnalysis(analysis))
+	return nil
+}
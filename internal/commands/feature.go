@@ -38,6 +38,8 @@ Examples:
 
 	cmd.Flags().StringP("epic", "e", "", "Explicitly link to an epic (e.g., E001)")
 	cmd.Flags().StringP("status", "s", "planning", "Feature status (planning, in-progress, done)")
+	cmd.Flags().BoolP("yes", "y", false, "Create the feature even if similar features exist")
+	addLanguageFlag(cmd)
 
 	return cmd
 }
@@ -73,6 +75,12 @@ func runFeature(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to load work items: %w", err)
 	}
 
+	// Warn before creating a near-duplicate
+	skipPrompt, _ := cmd.Flags().GetBool("yes")
+	if err := checkDuplicates(relationships.Feature, description, items, skipPrompt); err != nil {
+		return err
+	}
+
 	// Find or create parent epic
 	var parentEpic *relationships.WorkItem
 	var createNewEpic bool
@@ -101,15 +109,10 @@ func runFeature(cmd *cobra.Command, args []string) error {
 	// If we need to create a new epic, do it now
 	if createNewEpic {
 		fmt.Println(" Creating new parent epic...")
-		epicPrompt := fmt.Sprintf(`Create an epic description for a feature described as:
-"%s"
-
-The epic should:
-1. Be broader in scope than the feature
-2. Provide strategic context
-3. Allow for related features
-
-Respond with a concise but comprehensive epic description.`, description)
+		epicPrompt, err := ai.RenderPrompt("feature.epic", ai.PromptData{"Description": description})
+		if err != nil {
+			return err
+		}
 
 		epicContent, err := client.Ask(context.Background(), epicPrompt)
 		if err != nil {
@@ -164,17 +167,13 @@ Last Updated: %s
 	}
 
 	// Generate feature content
-	featurePrompt := fmt.Sprintf(`Create a detailed feature description for:
-"%s"
-
-Consider this epic's context:
-%s
-
-The description should include:
-1. Specific functionality to be implemented
-2. User value and benefits
-3. Technical considerations
-4. Success criteria`, description, parentEpic.Description)
+	featurePrompt, err := ai.RenderPrompt("feature.description", ai.PromptData{
+		"Description": description,
+		"EpicContext": parentEpic.Description,
+	})
+	if err != nil {
+		return err
+	}
 
 	fmt.Println(" Generating detailed feature description...")
 	featureContent, err := client.Ask(context.Background(), featurePrompt)
@@ -242,13 +241,13 @@ Epic: [%s] %s
 
 	var tasks []relationships.WorkItem
 	for _, taskTitle := range taskTitles {
-		taskPrompt := fmt.Sprintf(`Create a detailed task description for:
-"%s"
-
-This task is part of the feature:
-"%s"
-
-The description should be specific, actionable, and include clear success criteria.`, taskTitle, description)
+		taskPrompt, err := ai.RenderPrompt("task.child", ai.PromptData{
+			"Title":   taskTitle,
+			"Feature": description,
+		})
+		if err != nil {
+			return err
+		}
 
 		taskContent, err := client.Ask(context.Background(), taskPrompt)
 		if err != nil {
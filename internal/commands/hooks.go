@@ -0,0 +1,317 @@
+package commands
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/baudevs/yolo.baudevs.com/internal/ai"
+	"github.com/baudevs/yolo.baudevs.com/internal/commitmsg"
+	"github.com/baudevs/yolo.baudevs.com/internal/config"
+	"github.com/baudevs/yolo.baudevs.com/internal/git"
+	"github.com/baudevs/yolo.baudevs.com/internal/license"
+	"github.com/baudevs/yolo.baudevs.com/internal/logging"
+	"github.com/spf13/cobra"
+)
+
+// hookMarker identifies the hooks written by yolo, so they can be replaced
+// and removed without touching anybody else's
+const hookMarker = "# Installed by yolo hooks install"
+
+// hookScript runs a yolo subcommand from a git hook
+const hookScript = `#!/bin/sh
+%s
+YOLO=%q
+[ -x "$YOLO" ] || YOLO=yolo
+exec "$YOLO" hooks %s "$@"
+`
+
+// NewHooksCommand returns a new hooks command
+func NewHooksCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "hooks",
+		Short: "Install git hooks that check commit messages",
+		Long: `Install git hooks that keep commit messages in the Conventional Commits format.
+
+The commit-msg hook lints every message, however it was written, and rejects
+it when it breaks a rule: the header must be "type(scope): subject", the type
+and scope must be allowed, the first line must fit the length limit and the
+footers must be well formed. The rules come from yolo/settings/config.yml:
+
+  commit:
+    types: [feat, fix, docs, refactor, test, chore]
+    scopes: [cli, ai, git]
+    require_scope: true
+    max_subject_length: 72
+
+With --prefill, a prepare-commit-msg hook also fills the editor opened by a
+plain "git commit" with an AI suggestion for the staged changes.
+
+Examples:
+  yolo hooks install
+  yolo hooks install --prefill
+  echo "feat(cli): add hooks" | yolo hooks lint
+  yolo hooks uninstall`,
+	}
+
+	cmd.AddCommand(
+		newHooksInstallCommand(),
+		newHooksUninstallCommand(),
+		newHooksLintCommand(),
+		newHooksPrefillCommand(),
+	)
+
+	return cmd
+}
+
+func newHooksInstallCommand() *cobra.Command {
+	var prefill bool
+	var force bool
+
+	cmd := &cobra.Command{
+		Use:   "install",
+		Short: "Install the commit-msg hook, and prepare-commit-msg with --prefill",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dir, err := hooksDir()
+			if err != nil {
+				return err
+			}
+			if err := os.MkdirAll(dir, 0755); err != nil {
+				return fmt.Errorf("failed to create hooks directory: %w", err)
+			}
+
+			yolo, err := os.Executable()
+			if err != nil {
+				yolo = "yolo"
+			}
+
+			hooks := map[string]string{"commit-msg": "lint"}
+			if prefill {
+				hooks["prepare-commit-msg"] = "prefill"
+			}
+			for _, name := range []string{"commit-msg", "prepare-commit-msg"} {
+				sub, ok := hooks[name]
+				if !ok {
+					continue
+				}
+
+				path := filepath.Join(dir, name)
+				if err := backupHook(path, force); err != nil {
+					return err
+				}
+				script := fmt.Sprintf(hookScript, hookMarker, yolo, sub)
+				if err := os.WriteFile(path, []byte(script), 0755); err != nil {
+					return fmt.Errorf("failed to write %s hook: %w", name, err)
+				}
+				fmt.Printf("✅ Installed %s\n", path)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&prefill, "prefill", false, "Also prefill the commit message with an AI suggestion")
+	cmd.Flags().BoolVarP(&force, "force", "f", false, "Replace existing hooks, keeping a .bak copy")
+
+	return cmd
+}
+
+func newHooksUninstallCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "uninstall",
+		Short: "Remove the hooks installed by yolo",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dir, err := hooksDir()
+			if err != nil {
+				return err
+			}
+
+			removed := 0
+			for _, name := range []string{"commit-msg", "prepare-commit-msg"} {
+				path := filepath.Join(dir, name)
+				if !isYoloHook(path) {
+					continue
+				}
+				if err := os.Remove(path); err != nil {
+					return fmt.Errorf("failed to remove %s hook: %w", name, err)
+				}
+				removed++
+				fmt.Printf("🗑️  Removed %s\n", path)
+
+				// Put back the hook that was there before
+				if _, err := os.Stat(path + ".bak"); err == nil {
+					if err := os.Rename(path+".bak", path); err != nil {
+						return fmt.Errorf("failed to restore %s hook: %w", name, err)
+					}
+					fmt.Printf("↩️  Restored the previous %s hook\n", name)
+				}
+			}
+			if removed == 0 {
+				fmt.Println("No yolo hooks are installed")
+			}
+			return nil
+		},
+	}
+}
+
+func newHooksLintCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "lint [message-file]",
+		Short: "Check a commit message, read from a file or stdin",
+		Long: `Check a commit message against the project's rules. This is what the
+commit-msg hook runs; it also works in CI, e.g.:
+
+  git log -1 --format=%B | yolo hooks lint`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var data []byte
+			var err error
+			if len(args) == 0 || args[0] == "-" {
+				data, err = io.ReadAll(os.Stdin)
+			} else {
+				data, err = os.ReadFile(args[0])
+			}
+			if err != nil {
+				return fmt.Errorf("failed to read commit message: %w", err)
+			}
+
+			problems := commitmsg.Lint(string(data), commitRules())
+			for _, p := range problems {
+				fmt.Fprintf(os.Stderr, "  %s %s\n", problemIcon(p), p.Message)
+			}
+			if commitmsg.HasErrors(problems) {
+				cmd.SilenceUsage = true
+				return fmt.Errorf("the commit message breaks the project's conventions (see above)")
+			}
+			return nil
+		},
+	}
+}
+
+func newHooksPrefillCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:    "prefill <message-file> [source] [commit]",
+		Short:  "Fill a commit message file with an AI suggestion",
+		Hidden: true, // Run by the prepare-commit-msg hook
+		Args:   cobra.RangeArgs(1, 3),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// Only plain "git commit": not -m, -F, templates, merges or amends
+			if len(args) > 1 && args[1] != "" {
+				return nil
+			}
+
+			// A failing suggestion must never block the commit
+			if err := prefillCommitMessage(cmd, args[0]); err != nil {
+				fmt.Fprintf(os.Stderr, "yolo: no commit message suggestion: %v\n", err)
+			}
+			return nil
+		},
+	}
+}
+
+// prefillCommitMessage writes an AI suggestion above the comments git put in the file
+func prefillCommitMessage(cmd *cobra.Command, path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	if commitmsg.StripComments(string(data)) != "" {
+		return nil // The author already has a message
+	}
+
+	diff, err := git.Run("", "diff", "--cached", "--no-color", "--no-ext-diff")
+	if err != nil {
+		return err
+	}
+	if strings.TrimSpace(diff) == "" {
+		return nil
+	}
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	licenseManager, err := license.NewManager()
+	if err != nil {
+		return fmt.Errorf("failed to create license manager: %w", err)
+	}
+	client, err := ai.NewClient(cfg, licenseManager)
+	if err != nil {
+		return fmt.Errorf("failed to create AI client: %w", err)
+	}
+
+	redactor, err := ai.DefaultRedactor()
+	if err != nil {
+		return err
+	}
+	diff, _ = redactor.RedactDiff(diff)
+	applyCommitStyle()
+
+	formatter, err := commitFormatter()
+	if err != nil {
+		return err
+	}
+	message, _, err := ai.NewCommitAI(client).WithFormatter(formatter).GenerateCommitMessage(cmd.Context(), diff)
+	if err != nil {
+		return err
+	}
+
+	content := strings.TrimSpace(message) + "\n\n# Suggested by yolo, edit it as needed.\n" + string(data)
+	return os.WriteFile(path, []byte(content), 0644)
+}
+
+// commitRules returns the project's commit message rules, or the defaults
+func commitRules() commitmsg.Rules {
+	settings, err := config.LoadProjectSettings()
+	if err != nil {
+		logging.Warn("using the default commit rules", "error", err)
+		return commitmsg.Rules{}
+	}
+	return commitmsg.Rules{
+		Types:            settings.Commit.Types,
+		Scopes:           settings.Commit.Scopes,
+		RequireScope:     settings.Commit.RequireScope,
+		MaxSubjectLength: settings.Commit.MaxSubjectLength,
+	}
+}
+
+func problemIcon(p commitmsg.Problem) string {
+	if p.Level == commitmsg.LevelError {
+		return "❌"
+	}
+	return "⚠️ "
+}
+
+// hooksDir returns the repository's hooks directory, honoring core.hooksPath
+func hooksDir() (string, error) {
+	output, err := git.Run("", "rev-parse", "--git-path", "hooks")
+	if err != nil {
+		return "", fmt.Errorf("not in a git repository: %w", err)
+	}
+	return strings.TrimSpace(output), nil
+}
+
+// isYoloHook reports whether the hook at path was installed by yolo
+func isYoloHook(path string) bool {
+	data, err := os.ReadFile(path)
+	return err == nil && strings.Contains(string(data), hookMarker)
+}
+
+// backupHook makes room for a yolo hook: another tool's hook is kept as .bak
+// with --force, and is otherwise left alone with an error
+func backupHook(path string, force bool) error {
+	if _, err := os.Stat(path); os.IsNotExist(err) || isYoloHook(path) {
+		return nil
+	}
+	if !force {
+		return fmt.Errorf("%s already exists; use --force to replace it (a .bak copy is kept)", path)
+	}
+	if err := os.Rename(path, path+".bak"); err != nil {
+		return fmt.Errorf("failed to back up %s: %w", path, err)
+	}
+	fmt.Printf("📦 Kept the previous hook as %s.bak\n", path)
+	return nil
+}
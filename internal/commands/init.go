@@ -31,6 +31,7 @@ type ProjectConfig struct {
 	CustomPrompts          bool     `yaml:"custom_prompts"`
 	FolderStructure        []string `yaml:"folder_structure"`
 	Description            string   `yaml:"description"`
+	ContentLanguage        string   `yaml:"content_language,omitempty"`
 }
 
 // InitCmd returns the init command
@@ -145,6 +146,8 @@ The initialization process includes an AI-powered project planning phase.`,
 		},
 	}
 
+	addLanguageFlag(cmd)
+
 	return cmd
 }
 
@@ -0,0 +1,54 @@
+package commands
+
+import (
+	"log/slog"
+	"os"
+
+	"github.com/baudevs/yolo.baudevs.com/internal/config"
+	"github.com/baudevs/yolo.baudevs.com/internal/logging"
+)
+
+// SetupLogging configures the logger from the log settings of the config,
+// --verbose raising the level to info and --debug to debug
+func SetupLogging(verbose, debug bool) {
+	var settings config.LogConfig
+	if path, err := config.GetConfigPath(); err == nil {
+		if _, err := os.Stat(path); err == nil {
+			if cfg, err := config.LoadConfig(); err == nil {
+				settings = cfg.Log
+			}
+		}
+	}
+
+	opts := logging.Options{
+		Level:    logging.DefaultLevel,
+		File:     settings.File,
+		MaxSize:  int64(settings.MaxSizeMB) << 20,
+		MaxFiles: settings.MaxFiles,
+		Prompts:  settings.Prompts,
+	}
+	var levelErr error
+	if settings.Level != "" {
+		opts.Level, levelErr = logging.ParseLevel(settings.Level)
+	}
+	switch {
+	case debug:
+		opts.Level = slog.LevelDebug
+	case verbose && opts.Level > slog.LevelInfo:
+		opts.Level = slog.LevelInfo
+	}
+
+	switch opts.File {
+	case "off":
+		opts.File = ""
+	case "":
+		opts.File, _ = logging.DefaultFile()
+	}
+
+	if err := logging.Setup(opts); err != nil {
+		logging.Warn("logging to stderr only", "error", err)
+	}
+	if levelErr != nil {
+		logging.Warn("invalid log.level in config", "error", levelErr)
+	}
+}
@@ -0,0 +1,194 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/baudevs/yolo.baudevs.com/internal/ai"
+	"github.com/baudevs/yolo.baudevs.com/internal/config"
+	"github.com/baudevs/yolo.baudevs.com/internal/license"
+	"github.com/baudevs/yolo.baudevs.com/internal/relationships"
+	"github.com/baudevs/yolo.baudevs.com/internal/retrieval"
+	"github.com/spf13/cobra"
+)
+
+// planSection is the task section the plan is written to
+const planSection = "Implementation Plan"
+
+// NewPlanCommand returns a new plan command
+func NewPlanCommand() *cobra.Command {
+	var (
+		budget     int
+		candidates int
+		dryRun     bool
+		skipPrompt bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "plan <task-ID>",
+		Short: "Generate an implementation plan for a task from the codebase",
+		Long: `Generate a step-by-step implementation plan for a task.
+
+The task and its parent feature and epic are read, the source files they
+mention by path or symbol and the files most relevant to them are searched
+for in the repository, and the AI writes a plan naming the files and
+functions to change and the tests to add. The plan is appended to the task
+under a "## Implementation Plan" section.
+
+Examples:
+  yolo plan T003
+  yolo plan T003 --dry-run
+  yolo plan T003 --budget 6000`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// Load config
+			cfg, err := config.LoadConfig()
+			if err != nil {
+				return fmt.Errorf("failed to load config: %w", err)
+			}
+
+			// Create license manager
+			licenseManager, err := license.NewManager()
+			if err != nil {
+				return fmt.Errorf("failed to create license manager: %w", err)
+			}
+
+			// Create AI client
+			client, err := ai.NewClient(cfg, licenseManager)
+			if err != nil {
+				return fmt.Errorf("failed to create AI client: %w", err)
+			}
+
+			relManager := relationships.NewManager(client)
+			items, err := relManager.LoadWorkItems(relationships.Epic, relationships.Feature, relationships.Task)
+			if err != nil {
+				return fmt.Errorf("failed to load work items: %w", err)
+			}
+
+			var task *relationships.WorkItem
+			for i := range items {
+				if items[i].Type == relationships.Task && strings.EqualFold(items[i].ID, args[0]) {
+					task = &items[i]
+					break
+				}
+			}
+			if task == nil {
+				return fmt.Errorf("task %s not found", args[0])
+			}
+
+			if !dryRun && !skipPrompt && task.HasSection(planSection) {
+				ok, err := confirm(fmt.Sprintf("%s already has an implementation plan. Replace it?", task.ID))
+				if err != nil {
+					return err
+				}
+				if !ok {
+					return nil
+				}
+			}
+
+			parents := relationships.Ancestors(*task, items)
+			prompt, sources, err := buildPlanPrompt(*task, parents, budget, candidates)
+			if err != nil {
+				return err
+			}
+
+			fmt.Printf("🧭 Planning [%s] %s with %d source excerpts...\n", task.ID, task.Title, len(sources))
+			plan, err := client.Ask(cmd.Context(), prompt)
+			if err != nil {
+				return fmt.Errorf("failed to generate plan: %w", err)
+			}
+			plan = strings.TrimSpace(plan)
+
+			fmt.Printf("\n%s\n", plan)
+			if len(sources) > 0 {
+				fmt.Println("\n📚 Sources:")
+				for _, source := range sources {
+					fmt.Printf("  - %s\n", source.Citation())
+				}
+			}
+
+			if dryRun {
+				return nil
+			}
+			if err := relManager.SetSection(*task, planSection, plan); err != nil {
+				return fmt.Errorf("failed to save plan: %w", err)
+			}
+			fmt.Printf("\n✅ Plan saved to %s\n", task.Path)
+			return nil
+		},
+	}
+
+	cmd.Flags().IntVar(&budget, "budget", 4000, "Maximum tokens of source code to send")
+	cmd.Flags().IntVar(&candidates, "candidates", 20, "Number of source matches considered")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Print the plan without saving it to the task")
+	cmd.Flags().BoolVarP(&skipPrompt, "yes", "y", false, "Replace an existing plan without asking")
+	addLanguageFlag(cmd)
+
+	return cmd
+}
+
+// buildPlanPrompt finds the source code relevant to a task: files and symbols
+// the task mentions come first, then the best keyword matches
+func buildPlanPrompt(task relationships.WorkItem, parents []relationships.WorkItem, budget, candidates int) (string, []retrieval.Result, error) {
+	root, err := getProjectRoot()
+	if err != nil {
+		root = "."
+	}
+
+	docs, err := retrieval.Collect(root)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to collect source files: %w", err)
+	}
+
+	// Files excluded from redaction are never sent, not even in part
+	redactor, err := ai.DefaultRedactor()
+	if err != nil {
+		return "", nil, err
+	}
+	var sources []retrieval.Document
+	for _, doc := range docs {
+		if doc.Kind == retrieval.KindSource && !redactor.ExcludesPath(doc.Path) {
+			sources = append(sources, doc)
+		}
+	}
+
+	var parentText strings.Builder
+	for _, parent := range parents {
+		fmt.Fprintf(&parentText, "- %s [%s] %s: %s\n", parent.Type, parent.ID, parent.Title, parent.Description)
+	}
+
+	query := task.Title + "\n" + task.Description + "\n" + parentText.String()
+	results := retrieval.MatchSymbols(sources, retrieval.Symbols(query), candidates)
+	seen := make(map[string]bool, len(results))
+	for _, r := range results {
+		seen[r.Citation()] = true
+	}
+	for _, r := range retrieval.NewIndex(sources).Search(query, candidates) {
+		if len(results) >= candidates {
+			break
+		}
+		if !seen[r.Citation()] {
+			results = append(results, r)
+		}
+	}
+
+	projectContext, used := retrieval.Pack(results, budget)
+	example := "internal/foo/bar.go: DoThing"
+	if len(used) > 0 {
+		example = used[0].Path
+	} else {
+		projectContext = "(no relevant source files found)"
+	}
+
+	prompt, err := ai.RenderPrompt("task.plan", ai.PromptData{
+		"Task":    strings.TrimSpace(task.Content),
+		"Parents": strings.TrimSpace(parentText.String()),
+		"Context": projectContext,
+		"Example": example,
+	})
+	if err != nil {
+		return "", nil, err
+	}
+
+	return prompt, used, nil
+}
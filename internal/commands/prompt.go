@@ -3,9 +3,9 @@ package commands
 import (
 	"fmt"
 	"os"
-	"os/exec"
 	"path/filepath"
 
+	"github.com/baudevs/yolo.baudevs.com/internal/ai"
 	"github.com/baudevs/yolo.baudevs.com/internal/config"
 	"github.com/baudevs/yolo.baudevs.com/internal/messages"
 	"github.com/spf13/cobra"
@@ -149,6 +149,7 @@ func NewPromptCommand() *cobra.Command {
 
 	promptCmd.AddCommand(
 		newPromptListCommand(),
+		newPromptShowCommand(),
 		newPromptSetCommand(),
 		newPromptPersonalityCommand(),
 	)
@@ -160,29 +161,88 @@ func newPromptListCommand() *cobra.Command {
 	return &cobra.Command{
 		Use:   "list",
 		Short: "List available prompts",
-		Long:  "List all available prompts and their current values",
+		Long:  "List all prompts known to YOLO and where their effective version comes from",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			// Load client config
-			clientConfig, err := config.LoadClientConfig()
+			registry, err := ai.LoadPrompts()
 			if err != nil {
-				return fmt.Errorf("failed to load client config: %w", err)
+				return fmt.Errorf("failed to load prompts: %w", err)
 			}
 
 			fmt.Println("📝 Available Prompts")
 			fmt.Println("-----------------")
 
-			if len(clientConfig.Prompts) == 0 {
-				fmt.Println("No prompts configured")
+			for _, name := range registry.Names() {
+				p, _ := registry.Get(name)
+				fmt.Printf("%-26s %-8s %s\n", name, p.Source, p.Description)
+			}
+
+			fmt.Println("\nUse 'yolo prompt show <name> --effective' to see a prompt's template")
+			return nil
+		},
+	}
+}
+
+func newPromptShowCommand() *cobra.Command {
+	var effective bool
+
+	cmd := &cobra.Command{
+		Use:   "show <name>",
+		Short: "Show a prompt",
+		Long: `Show the templates defined for a prompt in every layer (default, global, project).
+
+With --effective only the template that will actually be sent is shown, with
+the instructions added for the repository's commit style and the project's
+content language.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			registry, err := ai.LoadPrompts()
+			if err != nil {
+				return fmt.Errorf("failed to load prompts: %w", err)
+			}
+
+			name := args[0]
+			layers := registry.Layers(name)
+			if len(layers) == 0 {
+				return fmt.Errorf("unknown prompt: %s (see 'yolo prompt list')", name)
+			}
+
+			if effective {
+				if registry.IsCommitStyle(name) {
+					applyCommitStyle()
+				}
+				text, err := registry.Effective(name)
+				if err != nil {
+					return err
+				}
+				p := layers[len(layers)-1]
+				fmt.Printf("📝 %s (%s)\n", p.Name, p.Source)
+				if p.Description != "" {
+					fmt.Println(p.Description)
+				}
+				fmt.Println("-----------------")
+				fmt.Println(text)
 				return nil
 			}
 
-			for key, value := range clientConfig.Prompts {
-				fmt.Printf("%s:\n%s\n\n", key, value)
+			for i, p := range layers {
+				if i > 0 {
+					fmt.Println()
+				}
+				fmt.Printf("📝 %s (%s)\n", p.Name, p.Source)
+				if p.Description != "" {
+					fmt.Println(p.Description)
+				}
+				fmt.Println("-----------------")
+				fmt.Println(p.Template)
 			}
 
 			return nil
 		},
 	}
+
+	cmd.Flags().BoolVar(&effective, "effective", false, "Show only the template that will be sent, with the added instructions")
+
+	return cmd
 }
 
 func newPromptSetCommand() *cobra.Command {
@@ -199,6 +259,11 @@ func newPromptSetCommand() *cobra.Command {
 				return fmt.Errorf("failed to load client config: %w", err)
 			}
 
+			// Reject templates that would fail at render time
+			if _, err := ai.ParsePromptTemplate(ai.Prompt{Name: key, Template: value, Source: ai.PromptSourceGlobal}); err != nil {
+				return err
+			}
+
 			// Initialize prompts map if needed
 			if clientConfig.Prompts == nil {
 				clientConfig.Prompts = make(map[string]string)
@@ -217,7 +282,7 @@ func newPromptSetCommand() *cobra.Command {
 		},
 	}
 
-	cmd.Flags().StringVarP(&key, "key", "k", "", "Prompt key (e.g., commit, error, ask)")
+	cmd.Flags().StringVarP(&key, "key", "k", "", "Prompt name (see 'yolo prompt list')")
 	cmd.Flags().StringVarP(&value, "value", "v", "", "Prompt value")
 	cmd.MarkFlagRequired("key")
 	cmd.MarkFlagRequired("value")
@@ -278,18 +343,8 @@ func editFile(file string, content interface{}) error {
 		return err
 	}
 
-	// Get editor from environment or use default
-	editor := os.Getenv("EDITOR")
-	if editor == "" {
-		editor = "vim"
-	}
-
 	// Open file in editor
-	cmd := exec.Command(editor, file)
-	cmd.Stdin = os.Stdin
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-	return cmd.Run()
+	return runEditor(file)
 }
 
 func loadMethodologyPrompts() (*MessagePrompts, error) {
@@ -0,0 +1,238 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/baudevs/yolo.baudevs.com/internal/ai"
+	"github.com/baudevs/yolo.baudevs.com/internal/config"
+	"github.com/baudevs/yolo.baudevs.com/internal/git"
+	"github.com/baudevs/yolo.baudevs.com/internal/license"
+	"github.com/baudevs/yolo.baudevs.com/internal/review"
+	"github.com/baudevs/yolo.baudevs.com/internal/version"
+	"github.com/spf13/cobra"
+)
+
+// severityIcons are shown in front of findings
+var severityIcons = map[string]string{
+	review.SeverityError:   "🔴",
+	review.SeverityWarning: "🟡",
+	review.SeverityInfo:    "🔵",
+}
+
+// NewReviewCommand returns a new review command
+func NewReviewCommand() *cobra.Command {
+	var (
+		staged       bool
+		contextLines int
+		reportPath   string
+		reportFormat string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "review [base..head | commit]",
+		Short: "Review staged changes or a commit range with AI",
+		Long: `Review changes with AI and list the findings grouped by file.
+
+Without arguments, or with --staged, the staged changes are reviewed. Give a
+range such as main..HEAD to review a branch, or a single commit. Each file's
+hunks are sent with surrounding context, and every finding has a line,
+severity, category and suggestion.
+
+Findings are linked to the work items (e.g. T003) referenced by the branch
+name and by the commits that touch their file. Use --report to also write
+them as a markdown or SARIF file.
+
+Examples:
+  yolo review
+  yolo review main..HEAD
+  yolo review HEAD~3..HEAD --report review.sarif`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if staged && len(args) > 0 {
+				return fmt.Errorf("use either --staged or a commit range")
+			}
+
+			root, err := getProjectRoot()
+			if err != nil {
+				return err
+			}
+
+			diffArgs := []string{"-U" + strconv.Itoa(contextLines)}
+			revRange := ""
+			title := "Review of staged changes"
+			if len(args) > 0 {
+				revRange = args[0]
+				if !strings.Contains(revRange, "..") {
+					revRange += "^!" // A single commit
+				}
+				diffArgs = append(diffArgs, revRange)
+				title = "Review of " + args[0]
+			} else {
+				diffArgs = append(diffArgs, "--cached")
+			}
+
+			files, err := git.NewGitOps(root).Diff(diffArgs...)
+			if err != nil {
+				return err
+			}
+
+			// Files excluded from redaction are never sent, not even in part
+			redactor, err := ai.DefaultRedactor()
+			if err != nil {
+				return err
+			}
+			var reviewable []git.FileDiff
+			for _, f := range review.Reviewable(files) {
+				if redactor.ExcludesPath(f.Path) {
+					fmt.Printf("🔒 Not sending the content of %s to the AI\n", f.Path)
+					continue
+				}
+				reviewable = append(reviewable, f)
+			}
+			if len(reviewable) == 0 {
+				if revRange == "" {
+					return fmt.Errorf("no staged changes to review")
+				}
+				return fmt.Errorf("no changes to review in %s", args[0])
+			}
+
+			// Load config
+			cfg, err := config.LoadConfig()
+			if err != nil {
+				return fmt.Errorf("failed to load config: %w", err)
+			}
+
+			// Create license manager
+			licenseManager, err := license.NewManager()
+			if err != nil {
+				return fmt.Errorf("failed to create license manager: %w", err)
+			}
+
+			// Create AI client
+			client, err := ai.NewClient(cfg, licenseManager)
+			if err != nil {
+				return fmt.Errorf("failed to create AI client: %w", err)
+			}
+
+			fmt.Printf("🔍 Reviewing %d files...\n", len(reviewable))
+			findings, err := review.Review(cmd.Context(), client, reviewable)
+			if err != nil {
+				return err
+			}
+
+			shared, byFile := reviewTaskRefs(root, revRange)
+			review.LinkTasks(findings, shared, byFile)
+
+			printFindings(findings)
+
+			if reportPath != "" {
+				if err := writeReviewReport(reportPath, reportFormat, title, findings); err != nil {
+					return err
+				}
+				fmt.Printf("\n📝 Report written to %s\n", reportPath)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&staged, "staged", false, "Review the staged changes (the default)")
+	cmd.Flags().IntVarP(&contextLines, "context", "U", 10, "Lines of context around each hunk")
+	cmd.Flags().StringVarP(&reportPath, "report", "o", "", "Also write the findings to this file")
+	cmd.Flags().StringVar(&reportFormat, "format", "", "Report format: markdown or sarif (default: from the file extension)")
+
+	return cmd
+}
+
+// printFindings shows the findings grouped by file
+func printFindings(findings []review.Finding) {
+	if len(findings) == 0 {
+		fmt.Println("\n✅ No findings, the changes look good")
+		return
+	}
+
+	for _, group := range review.GroupByFile(findings) {
+		fmt.Printf("\n📄 %s\n", group[0].File)
+		for _, f := range group {
+			fmt.Printf("  %s %d [%s] %s\n", severityIcons[f.Severity], f.Line, f.Category, f.Message)
+			if f.Suggestion != "" {
+				fmt.Printf("     💡 %s\n", strings.ReplaceAll(f.Suggestion, "\n", "\n        "))
+			}
+			if len(f.Tasks) > 0 {
+				fmt.Printf("     🔗 %s\n", strings.Join(f.Tasks, ", "))
+			}
+		}
+	}
+
+	counts := review.Counts(findings)
+	fmt.Printf("\n%d findings: %d errors, %d warnings, %d info\n",
+		len(findings), counts[review.SeverityError], counts[review.SeverityWarning], counts[review.SeverityInfo])
+}
+
+// writeReviewReport saves the findings as markdown or SARIF
+func writeReviewReport(path, format, title string, findings []review.Finding) error {
+	if format == "" {
+		switch strings.ToLower(filepath.Ext(path)) {
+		case ".sarif", ".json":
+			format = "sarif"
+		default:
+			format = "markdown"
+		}
+	}
+
+	var data []byte
+	switch format {
+	case "markdown", "md":
+		data = []byte(review.Markdown(title, findings))
+	case "sarif":
+		var err error
+		if data, err = review.SARIF(version.Version, findings); err != nil {
+			return fmt.Errorf("failed to create SARIF report: %w", err)
+		}
+	default:
+		return fmt.Errorf("unknown report format %q, use markdown or sarif", format)
+	}
+
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write report: %w", err)
+	}
+	return nil
+}
+
+// reviewTaskRefs finds the work item IDs behind the changes: those in the
+// branch name apply to every file, those in a commit message to the files
+// the commit touches
+func reviewTaskRefs(root, revRange string) ([]string, map[string][]string) {
+	gitOutput := func(args ...string) string {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = root
+		out, err := cmd.Output()
+		if err != nil {
+			return ""
+		}
+		return string(out)
+	}
+
+	shared := review.TaskRefs(gitOutput("rev-parse", "--abbrev-ref", "HEAD"))
+	byFile := make(map[string][]string)
+	if revRange == "" {
+		return shared, byFile
+	}
+
+	for _, commit := range strings.Fields(gitOutput("rev-list", revRange)) {
+		refs := review.TaskRefs(gitOutput("show", "-s", "--format=%B", commit))
+		if len(refs) == 0 {
+			continue
+		}
+		for _, file := range strings.Split(strings.TrimSpace(gitOutput("show", "--name-only", "--format=", commit)), "\n") {
+			if file != "" {
+				byFile[file] = append(byFile[file], refs...)
+			}
+		}
+	}
+	return shared, byFile
+}
@@ -0,0 +1,119 @@
+package commands
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/baudevs/yolo.baudevs.com/internal/git"
+	"github.com/baudevs/yolo.baudevs.com/internal/relationships"
+	"github.com/baudevs/yolo.baudevs.com/internal/review"
+	"github.com/spf13/cobra"
+)
+
+// linksSection matches the relationship lines of a work item
+var linksSection = regexp.MustCompile(`(?s)<!-- YOLO-LINKS-START -->\n(.*?)<!-- YOLO-LINKS-END -->`)
+
+// linkedCommit is a commit referencing a work item
+type linkedCommit struct {
+	Hash    string
+	Date    string
+	Author  string
+	Subject string
+}
+
+// NewShowCommand returns a new show command
+func NewShowCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "show <ID>",
+		Short: "Show a work item with its relationships and linked commits",
+		Long: `Show an epic, feature or task: its status, description, relationships and
+the commits that reference it, e.g. with a "Refs: T012" trailer.
+
+Examples:
+  yolo show T012
+  yolo show E002`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			items, err := relationships.NewManager(nil).LoadWorkItems(relationships.Epic, relationships.Feature, relationships.Task)
+			if err != nil {
+				return fmt.Errorf("failed to load work items: %w", err)
+			}
+
+			var item *relationships.WorkItem
+			for i := range items {
+				if strings.EqualFold(items[i].ID, args[0]) {
+					item = &items[i]
+					break
+				}
+			}
+			if item == nil {
+				return fmt.Errorf("work item %s not found", args[0])
+			}
+
+			fmt.Printf("[%s] %s\n", item.ID, item.Title)
+			fmt.Printf("%s · %s · %s\n", item.Type, item.Status, item.Path)
+			if item.Description != "" {
+				fmt.Printf("\n%s\n", item.Description)
+			}
+
+			if match := linksSection.FindStringSubmatch(item.Content); match != nil && strings.TrimSpace(match[1]) != "" {
+				fmt.Println("\n🔗 Relationships:")
+				fmt.Println(indent(strings.TrimSpace(match[1]), "  "))
+			}
+
+			commits, err := commitsReferencing(item.ID)
+			if err != nil {
+				return err
+			}
+			if len(commits) == 0 {
+				fmt.Println("\nNo commits reference this item yet")
+				return nil
+			}
+			fmt.Printf("\n📜 Commits (%d):\n", len(commits))
+			for _, c := range commits {
+				fmt.Printf("  %s %s %s (%s)\n", c.Hash, c.Date, c.Subject, c.Author)
+			}
+			return nil
+		},
+	}
+}
+
+// commitsReferencing returns the commits of the current branch whose message
+// mentions the work item ID, newest first
+func commitsReferencing(id string) ([]linkedCommit, error) {
+	// Unborn branches have no history yet
+	if _, err := git.Run("", "rev-parse", "--verify", "--quiet", "HEAD"); err != nil {
+		return nil, nil
+	}
+
+	output, err := git.Run("", "log", "--fixed-strings", "--regexp-ignore-case", "--grep="+id,
+		"--date=short", "--format=%h%x1f%ad%x1f%an%x1f%s%x1f%B%x1e")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the commit history: %w", err)
+	}
+
+	var commits []linkedCommit
+	for _, record := range strings.Split(output, "\x1e") {
+		fields := strings.SplitN(strings.TrimSpace(record), "\x1f", 5)
+		if len(fields) < 5 {
+			continue
+		}
+		// The grep also matches longer IDs, e.g. T0123 for T012
+		if !containsString(review.TaskRefs(fields[4]), strings.ToUpper(id)) {
+			continue
+		}
+		commits = append(commits, linkedCommit{Hash: fields[0], Date: fields[1], Author: fields[2], Subject: fields[3]})
+	}
+	return commits, nil
+}
+
+// containsString reports whether the list contains s
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
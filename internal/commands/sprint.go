@@ -230,6 +230,27 @@ func formatSprintEntry(content string) string {
 	return fmt.Sprintf("## Sprint Update - %s\n\n%s", timestamp, content)
 }
 
+// appendSprintEntry adds an entry to sprint.current.md, starting the sprint if needed
+func appendSprintEntry(root, content string) error {
+	sprintFile := filepath.Join(root, "sprint.current.md")
+
+	formattedContent := formatSprintEntry(content)
+	if _, err := os.Stat(sprintFile); err == nil {
+		formattedContent = "\n\n" + strings.Repeat("-", 80) + "\n\n" + formattedContent
+	}
+
+	f, err := os.OpenFile(sprintFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open sprint file: %w", err)
+	}
+	defer f.Close()
+
+	if _, err := f.WriteString(formattedContent); err != nil {
+		return fmt.Errorf("failed to update sprint file: %w", err)
+	}
+	return nil
+}
+
 // getProjectRoot returns the root directory of the project
 func getProjectRoot() (string, error) {
 	cmd := exec.Command("git", "rev-parse", "--show-toplevel")
@@ -0,0 +1,228 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/baudevs/yolo.baudevs.com/internal/ai"
+	"github.com/baudevs/yolo.baudevs.com/internal/config"
+	"github.com/baudevs/yolo.baudevs.com/internal/git"
+	"github.com/baudevs/yolo.baudevs.com/internal/license"
+	"github.com/spf13/cobra"
+)
+
+// backupRefPrefix is where the history replaced by a squash is kept
+const backupRefPrefix = "refs/yolo/backup/"
+
+// NewSquashCommand returns a new squash command
+func NewSquashCommand() *cobra.Command {
+	var message string
+	var summarizedDiff bool
+	var skipReview bool
+	var tasks []string
+
+	cmd := &cobra.Command{
+		Use:   "squash <base>",
+		Short: "Squash the commits since a base into one",
+		Long: `Squash the commits of the current branch since <base> (a branch, tag or
+commit, e.g. main) into a single commit with one consolidated message.
+
+The message is generated from the diff of the whole range and the messages of
+the squashed commits, leaving out fixup! and amend! commits, and is reviewed
+like with "yolo commit". Issue refs, co-authors and sign-offs of the squashed
+commits are kept.
+
+Before squashing, the current history is saved as a backup ref under
+refs/yolo/backup/, and the command to restore it is printed. Staged changes
+must be committed or unstaged first; unstaged changes are left alone.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// Load config
+			cfg, err := config.LoadConfig()
+			if err != nil {
+				return fmt.Errorf("failed to load config: %w", err)
+			}
+
+			// Create license manager
+			licenseManager, err := license.NewManager()
+			if err != nil {
+				return fmt.Errorf("failed to create license manager: %w", err)
+			}
+
+			// Create AI client
+			client, err := ai.NewClient(cfg, licenseManager)
+			if err != nil {
+				return fmt.Errorf("failed to create AI client: %w", err)
+			}
+
+			if _, err := git.Run("", "diff", "--cached", "--quiet"); err != nil {
+				return fmt.Errorf("there are staged changes, commit or unstage them before squashing")
+			}
+
+			g := git.NewGitOps("")
+			head, err := g.Head()
+			if err != nil {
+				return err
+			}
+			if head == "" {
+				return fmt.Errorf("there are no commits to squash yet")
+			}
+			output, err := git.Run("", "merge-base", args[0], "HEAD")
+			if err != nil {
+				return fmt.Errorf("failed to find where HEAD branched from %s: %w", args[0], err)
+			}
+			base := strings.TrimSpace(output)
+
+			messages, count, err := squashedMessages(base)
+			if err != nil {
+				return err
+			}
+			squashed, err := git.Run("", "log", "--format=%h", base+"..HEAD")
+			if err != nil {
+				return fmt.Errorf("failed to read the commits to squash: %w", err)
+			}
+			switch count {
+			case 0:
+				return fmt.Errorf("there are no commits since %s to squash", args[0])
+			case 1:
+				return fmt.Errorf("there is a single commit since %s, use \"yolo commit --amend\" to reword it", args[0])
+			}
+			warnIfPushed("HEAD")
+
+			var opts commitOptions
+			if opts.linked, err = linkedTasks(tasks); err != nil {
+				return err
+			}
+			if opts.trailers, err = commitTrailers(nil, false); err != nil {
+				return err
+			}
+			opts.trailers = append(keptTrailers(messages...), opts.trailers...)
+			if opts.formatter, err = commitFormatter(); err != nil {
+				return err
+			}
+
+			if message == "" {
+				opts.style = applyCommitStyle()
+
+				var diff string
+				if summarizedDiff {
+					diff, err = getSummarizedDiff(base)
+				} else {
+					diff, err = getFullDiff(base)
+				}
+				if err != nil {
+					return fmt.Errorf("failed to get diff: %w", err)
+				}
+
+				// Keep the content of sensitive files out of the request
+				redactor, err := ai.DefaultRedactor()
+				if err != nil {
+					return err
+				}
+				diff, excluded := redactor.RedactDiff(diff)
+				for _, path := range excluded {
+					fmt.Printf("🔒 Not sending the content of %s to the AI\n", path)
+				}
+
+				fmt.Printf("🤖 Writing one message for %d commits...\n", count)
+				var truncated bool
+				message, truncated, err = opts.commitAI(client).GenerateSquashMessage(cmd.Context(), diff, messages)
+				if err != nil {
+					return fmt.Errorf("failed to generate squash message: %w", err)
+				}
+				if truncated {
+					fmt.Println("\n⚠️  Note: Some changes were too large and were only summarized by their line counts.")
+					fmt.Println("The commit message may not reflect them in detail.")
+				}
+
+				warnNewScopes(opts.style, message)
+
+				if !skipReview {
+					message, err = reviewCommitMessage(cmd.Context(), client, opts.commitAI(client), diff, message)
+					if errors.Is(err, errCommitAborted) {
+						fmt.Println("🛑 Squash aborted, nothing was changed")
+						return nil
+					}
+					if err != nil {
+						return err
+					}
+				}
+			}
+
+			message = opts.finish(message)
+
+			backup, err := backupHead(head)
+			if err != nil {
+				return err
+			}
+			if err := g.ResetSoft(base); err != nil {
+				return fmt.Errorf("failed to squash: %w", err)
+			}
+			if err := createCommit(message); err != nil {
+				if restoreErr := g.ResetSoft(head); restoreErr != nil {
+					return fmt.Errorf("failed to create the squashed commit: %w (restore it with: git reset --soft %s)", err, backup)
+				}
+				return fmt.Errorf("failed to create the squashed commit, nothing was changed: %w", err)
+			}
+
+			fmt.Printf("\n✨ Squashed %d commits with message:\n%s\n", count, message)
+			fmt.Printf("\n💾 The previous history is saved as %s\n", backup)
+			fmt.Printf("   Restore it with: git reset --soft %s\n", backup)
+
+			if len(opts.linked) > 0 {
+				hash, err := headCommit()
+				if err != nil {
+					return fmt.Errorf("failed to read the new commit: %w", err)
+				}
+				recordCommit(opts.linked, hash, message, strings.Fields(squashed)...)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&message, "message", "m", "", "Use provided commit message instead of generating one")
+	cmd.Flags().BoolVarP(&summarizedDiff, "summarized", "s", false, "Send only file names and line counts to AI")
+	cmd.Flags().BoolVarP(&skipReview, "yes", "y", false, "Squash with the generated message without reviewing it")
+	cmd.Flags().StringSliceVarP(&tasks, "task", "t", nil, "Link the commit to these task IDs, in addition to the detected ones")
+
+	return cmd
+}
+
+// squashedMessages returns the messages of the commits since base, oldest
+// first, and how many commits there are. Messages of fixup! and amend!
+// commits are left out, their changes are in the diff.
+func squashedMessages(base string) ([]string, int, error) {
+	output, err := git.Run("", "log", "--reverse", "--format=%B%x1e", base+"..HEAD")
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to read the commits to squash: %w", err)
+	}
+
+	var messages []string
+	count := 0
+	for _, text := range strings.Split(output, "\x1e") {
+		if text = strings.TrimSpace(text); text == "" {
+			continue
+		}
+		count++
+		if strings.HasPrefix(text, "fixup! ") || strings.HasPrefix(text, "amend! ") {
+			continue
+		}
+		messages = append(messages, text)
+	}
+	return messages, count, nil
+}
+
+// backupHead saves commit under a backup ref named after the current branch
+func backupHead(commit string) (string, error) {
+	branch, err := git.Run("", "symbolic-ref", "--quiet", "--short", "HEAD")
+	if err != nil {
+		branch = "detached"
+	}
+	ref := backupRefPrefix + strings.TrimSpace(branch) + "-" + time.Now().Format("20060102-150405")
+	if _, err := git.Run("", "update-ref", ref, commit); err != nil {
+		return "", fmt.Errorf("failed to save the backup ref %s: %w", ref, err)
+	}
+	return ref, nil
+}
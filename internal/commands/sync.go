@@ -0,0 +1,273 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/baudevs/yolo.baudevs.com/internal/git"
+	"github.com/baudevs/yolo.baudevs.com/internal/models"
+	"github.com/spf13/cobra"
+)
+
+// NewSyncCommand returns a new sync command
+func NewSyncCommand() *cobra.Command {
+	var message string
+	var skipReview bool
+	var force bool
+	var tasks []string
+
+	cmd := &cobra.Command{
+		Use:   "sync",
+		Short: "Commit, pull and push the current branch",
+		Long: `Bring the current branch in sync with its remote: uncommitted changes are
+committed like with "yolo commit --all", the branch is rebased on its
+upstream and the result is pushed. A branch without an upstream is pushed
+to origin and tracks it.
+
+Conflicts stop the sync: they are listed, conflicts in yolo/ work items with
+the sections they are in, and the commands to resolve or undo the rebase are
+printed. When the branch rewrote commits that are already pushed (e.g. with
+"yolo commit --amend" or "yolo squash"), nothing is pushed unless --force is
+given, which pushes with --force-with-lease.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := git.NewClient()
+			if err != nil {
+				return err
+			}
+			if err := checkInProgress(client); err != nil {
+				return err
+			}
+
+			if !client.HasChanges() {
+				return syncBranch(models.CommitOptions{Force: force})
+			}
+
+			commit := NewCommitCommand()
+			commit.SetContext(cmd.Context())
+			flags := map[string]string{"all": "true", "sync": "true"}
+			if message != "" {
+				flags["message"] = message
+			}
+			if skipReview {
+				flags["yes"] = "true"
+			}
+			if force {
+				flags["force"] = "true"
+			}
+			if len(tasks) > 0 {
+				flags["task"] = strings.Join(tasks, ",")
+			}
+			for name, value := range flags {
+				if err := commit.Flags().Set(name, value); err != nil {
+					return fmt.Errorf("failed to set --%s: %w", name, err)
+				}
+			}
+			return commit.RunE(commit, nil)
+		},
+	}
+
+	cmd.Flags().StringVarP(&message, "message", "m", "", "Use provided commit message instead of generating one")
+	cmd.Flags().BoolVarP(&skipReview, "yes", "y", false, "Commit the generated message without reviewing it")
+	cmd.Flags().BoolVar(&force, "force", false, "Push a rewritten history with --force-with-lease")
+	cmd.Flags().StringSliceVarP(&tasks, "task", "t", nil, "Link the commit to these task IDs, in addition to the detected ones")
+
+	return cmd
+}
+
+// syncBranch rebases the current branch on its upstream and pushes it. It
+// stops on conflicts, and before force pushing unless opts.Force is set.
+func syncBranch(opts models.CommitOptions) error {
+	if opts.NoSync {
+		return nil
+	}
+
+	client, err := git.NewClient()
+	if err != nil {
+		return err
+	}
+	if err := checkInProgress(client); err != nil {
+		return err
+	}
+	branch := client.CurrentBranch()
+	if branch == "" {
+		return fmt.Errorf("HEAD is detached, check out a branch to sync it")
+	}
+
+	upstream := client.Upstream()
+	if upstream == "" {
+		remote, err := client.DefaultRemote()
+		if err != nil {
+			return fmt.Errorf("nothing to sync with: %w, add one with: git remote add origin <url>", err)
+		}
+		fmt.Printf("⬆️  Pushing %s to %s...\n", branch, remote)
+		if err := client.PushUpstream(remote, branch); err != nil {
+			return err
+		}
+		fmt.Printf("✅ %s is on %s/%s\n", branch, remote, branch)
+		return nil
+	}
+
+	fmt.Printf("🔄 Syncing %s with %s...\n", branch, upstream)
+	if err := client.Fetch(); err != nil {
+		return err
+	}
+	ahead, behind, err := client.Divergence()
+	if err != nil {
+		return err
+	}
+
+	if behind > 0 && client.UpstreamRewritten() {
+		if !opts.Force {
+			fmt.Printf("\n⚠️  %s rewrote commits that are already on %s (e.g. with an amend or a squash):\n", branch, upstream)
+			fmt.Printf("   %d local and %d remote commits differ. Pushing replaces the remote history.\n", ahead, behind)
+			fmt.Println("\nTo push it anyway, once nobody else builds on the old commits:")
+			fmt.Println("   run the same command again with --force (it uses --force-with-lease)")
+			fmt.Println("To drop the rewrite and take the remote history instead:")
+			fmt.Println("   git reset --keep @{upstream}")
+			return fmt.Errorf("sync stopped before force pushing %s", branch)
+		}
+		fmt.Printf("⬆️  Force pushing %s over %s...\n", branch, upstream)
+		if err := client.ForcePush(); err != nil {
+			return err
+		}
+		fmt.Printf("✅ %s replaced %s\n", branch, upstream)
+		return nil
+	}
+
+	if behind > 0 {
+		fmt.Printf("⬇️  Rebasing on %d new commits of %s...\n", behind, upstream)
+		if err := client.Pull(); err != nil {
+			if conflicts, _ := client.Conflicts(); len(conflicts) > 0 {
+				printConflicts(client, conflicts)
+				return fmt.Errorf("sync stopped: %d files have conflicts", len(conflicts))
+			}
+			if client.InProgress() == "rebase" {
+				fmt.Println("\nThe rebase stopped. Undo it with: git rebase --abort")
+			}
+			return err
+		}
+	}
+
+	if ahead == 0 {
+		fmt.Printf("✅ %s is up to date with %s\n", branch, upstream)
+		return nil
+	}
+
+	fmt.Printf("⬆️  Pushing %d commits to %s...\n", ahead, upstream)
+	if err := client.Push(); err != nil {
+		var gitErr *git.CommandError
+		if errors.As(err, &gitErr) && strings.Contains(gitErr.Output, "rejected") {
+			fmt.Printf("\n%s changed during the sync, run yolo sync again to rebase on it.\n", upstream)
+		}
+		return err
+	}
+	fmt.Printf("✅ %s is in sync with %s\n", branch, upstream)
+	return nil
+}
+
+// checkInProgress stops when a rebase or merge is waiting to be finished,
+// explaining how to finish or undo it
+func checkInProgress(client *git.Client) error {
+	op := client.InProgress()
+	if op == "" {
+		return nil
+	}
+	if conflicts, _ := client.Conflicts(); len(conflicts) > 0 {
+		printConflicts(client, conflicts)
+	} else {
+		fmt.Printf("\nFinish the %s with: git %s --continue\n", op, op)
+		fmt.Printf("Or undo it with:     git %s --abort\n", op)
+	}
+	return fmt.Errorf("a %s is in progress", op)
+}
+
+// printConflicts lists the conflicting files, the sections of the yolo work
+// items they are in, and how to resolve or undo the rebase
+func printConflicts(client *git.Client, conflicts []string) {
+	op := client.InProgress()
+
+	fmt.Printf("\n💥 %d files have conflicts:\n", len(conflicts))
+	workItems := false
+	for _, path := range conflicts {
+		sections, count := conflictSections(path)
+		switch {
+		case isWorkItemFile(path):
+			workItems = true
+			id := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+			fmt.Printf("   - %s (work item %s): %d conflicts", path, id, count)
+			if len(sections) > 0 {
+				fmt.Printf(" in %s", strings.Join(sections, ", "))
+			}
+			fmt.Println()
+		case count > 0:
+			fmt.Printf("   - %s: %d conflicts\n", path, count)
+		default:
+			fmt.Printf("   - %s\n", path)
+		}
+	}
+	if workItems {
+		fmt.Println("\nIn work items, keep the lines of both sides in lists such as Activity and")
+		fmt.Println("relationships, and pick one value for fields such as the status.")
+	}
+
+	fmt.Println("\nTo resolve the conflicts:")
+	fmt.Println("   1. Edit the files and remove the <<<<<<< ======= >>>>>>> markers")
+	fmt.Println("   2. git add <files>")
+	switch op {
+	case "rebase", "merge":
+		fmt.Printf("   3. git %s --continue, then yolo sync again\n", op)
+		fmt.Printf("To undo the %s and get your branch back as it was: git %s --abort\n", op, op)
+	default:
+		// The rebase went through, the uncommitted changes stashed for it did not apply
+		fmt.Println("   3. git stash drop, your uncommitted changes are kept in the stash until then")
+	}
+}
+
+// isWorkItemFile reports whether path is a markdown file of the yolo folder
+func isWorkItemFile(path string) bool {
+	return strings.HasPrefix(filepath.ToSlash(path), "yolo/") && strings.HasSuffix(path, ".md")
+}
+
+// conflictSections counts the conflicts of a file and the markdown sections
+// they are in, headings changed by a conflict included
+func conflictSections(path string) ([]string, int) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, 0
+	}
+
+	var sections []string
+	add := func(name string) {
+		if !containsString(sections, name) {
+			sections = append(sections, name)
+		}
+	}
+
+	section := "the title"
+	count := 0
+	inConflict, added := false, false
+	for _, line := range strings.Split(string(data), "\n") {
+		switch {
+		case strings.HasPrefix(line, "<<<<<<< "):
+			count++
+			inConflict, added = true, false
+		case strings.HasPrefix(line, ">>>>>>> "):
+			inConflict = false
+		case inConflict && (line == "=======" || strings.HasPrefix(line, "||||||| ")):
+		case strings.HasPrefix(line, "## "):
+			// "## Status: done" is the Status section
+			section, _, _ = strings.Cut(strings.TrimSpace(strings.TrimLeft(line, "#")), ":")
+			if inConflict {
+				add(section)
+				added = true
+			}
+		case inConflict && !added:
+			add(section)
+			added = true
+		}
+	}
+	return sections, count
+}
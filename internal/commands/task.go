@@ -37,6 +37,8 @@ Examples:
 
 	cmd.Flags().StringP("epic", "e", "", "Explicitly link to an epic (e.g., E001)")
 	cmd.Flags().StringP("status", "s", "planning", "Task status (planning, in-progress, done)")
+	cmd.Flags().BoolP("yes", "y", false, "Create the task even if similar tasks exist")
+	addLanguageFlag(cmd)
 
 	return cmd
 }
@@ -72,6 +74,12 @@ func runTask(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to load work items: %w", err)
 	}
 
+	// Warn before creating a near-duplicate
+	skipPrompt, _ := cmd.Flags().GetBool("yes")
+	if err := checkDuplicates(relationships.Task, description, items, skipPrompt); err != nil {
+		return err
+	}
+
 	// Find or create parent epic
 	var parentEpic *relationships.WorkItem
 	var createNewEpic bool
@@ -101,15 +109,10 @@ func runTask(cmd *cobra.Command, args []string) error {
 	// If we need to create a new epic, do it now
 	if createNewEpic {
 		fmt.Println(" Creating new parent epic...")
-		epicPrompt := fmt.Sprintf(`Create an epic description for a task described as:
-"%s"
-
-The epic should:
-1. Be broader in scope than the task
-2. Provide implementation context
-3. Allow for related tasks
-
-Respond with a concise but comprehensive epic description.`, description)
+		epicPrompt, err := ai.RenderPrompt("task.epic", ai.PromptData{"Description": description})
+		if err != nil {
+			return err
+		}
 
 		epicContent, err := client.Ask(context.Background(), epicPrompt)
 		if err != nil {
@@ -163,13 +166,13 @@ Last Updated: %s
 	}
 
 	// Generate task content
-	taskPrompt := fmt.Sprintf(`Create a detailed task description for:
-"%s"
-
-Consider this context:
-%s
-
-The description should be specific, actionable, and include clear success criteria.`, description, parentEpic.Description)
+	taskPrompt, err := ai.RenderPrompt("task.description", ai.PromptData{
+		"Description": description,
+		"EpicContext": parentEpic.Description,
+	})
+	if err != nil {
+		return err
+	}
 
 	fmt.Println(" Generating detailed task description...")
 	taskContent, err := client.Ask(context.Background(), taskPrompt)
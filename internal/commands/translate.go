@@ -0,0 +1,148 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/baudevs/yolo.baudevs.com/internal/ai"
+	"github.com/baudevs/yolo.baudevs.com/internal/config"
+	"github.com/baudevs/yolo.baudevs.com/internal/license"
+	"github.com/baudevs/yolo.baudevs.com/internal/relationships"
+	"github.com/spf13/cobra"
+)
+
+// NewTranslateCommand returns a new translate command
+func NewTranslateCommand() *cobra.Command {
+	var lang string
+
+	cmd := &cobra.Command{
+		Use:   "translate <ID>",
+		Short: "Translate an epic, feature or task into another language",
+		Long: `Translate a work item into another language.
+
+The translation is saved next to the original, in a translations/ folder
+(e.g. yolo/tasks/translations/T003.es.md), and both files link to each
+other. The original stays the source of truth: run the command again to
+refresh the translation after changing it.
+
+Examples:
+  yolo translate T003 --to es
+  yolo translate E001 --to fr`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if lang == "" {
+				return fmt.Errorf("please choose a language with --to, e.g. --to es")
+			}
+
+			// Load config
+			cfg, err := config.LoadConfig()
+			if err != nil {
+				return fmt.Errorf("failed to load config: %w", err)
+			}
+
+			// Create license manager
+			licenseManager, err := license.NewManager()
+			if err != nil {
+				return fmt.Errorf("failed to create license manager: %w", err)
+			}
+
+			// Create AI client
+			client, err := ai.NewClient(cfg, licenseManager)
+			if err != nil {
+				return fmt.Errorf("failed to create AI client: %w", err)
+			}
+
+			relManager := relationships.NewManager(client)
+			items, err := relManager.LoadWorkItems(relationships.Epic, relationships.Feature, relationships.Task)
+			if err != nil {
+				return fmt.Errorf("failed to load work items: %w", err)
+			}
+
+			var item *relationships.WorkItem
+			for i := range items {
+				if strings.EqualFold(items[i].ID, args[0]) {
+					item = &items[i]
+					break
+				}
+			}
+			if item == nil {
+				return fmt.Errorf("work item %s not found", args[0])
+			}
+
+			prompt, err := ai.RenderPrompt("translate", ai.PromptData{
+				"Language": ai.LanguageName(lang),
+				"ID":       item.ID,
+				"Content":  item.Content,
+			})
+			if err != nil {
+				return err
+			}
+
+			fmt.Printf("🌍 Translating [%s] %s into %s...\n", item.ID, item.Title, ai.LanguageName(lang))
+			translated, err := client.Ask(cmd.Context(), prompt)
+			if err != nil {
+				return fmt.Errorf("failed to translate %s: %w", item.ID, err)
+			}
+
+			path := relationships.TranslationPath(*item, lang)
+			content := translationContent(*item, lang, path, translated)
+			if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+				return fmt.Errorf("failed to create translations directory: %w", err)
+			}
+			if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+				return fmt.Errorf("failed to write translation: %w", err)
+			}
+
+			if err := relManager.LinkTranslation(*item, lang, path); err != nil {
+				return err
+			}
+
+			fmt.Printf("✅ Translation saved to %s\n", path)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&lang, "to", "", "Language to translate into, e.g. es")
+
+	return cmd
+}
+
+// translationContent cleans up the translated markdown and adds a note,
+// below the title, linking back to the original
+func translationContent(item relationships.WorkItem, lang, path, translated string) string {
+	translated = strings.TrimSpace(translated)
+	if strings.HasPrefix(translated, "```") {
+		translated = strings.TrimPrefix(translated, "```markdown")
+		translated = strings.TrimPrefix(translated, "```md")
+		translated = strings.TrimPrefix(translated, "```")
+		translated = strings.TrimSuffix(translated, "```")
+		translated = strings.TrimSpace(translated)
+	}
+
+	original, err := filepath.Rel(filepath.Dir(path), item.Path)
+	if err != nil {
+		original = item.Path
+	}
+	note := fmt.Sprintf("> Translation (%s) of [%s](%s). Edit the original, then run `yolo translate %s --to %s` to refresh it.",
+		lang, item.ID, filepath.ToSlash(original), item.ID, lang)
+
+	title, rest, found := strings.Cut(translated, "\n")
+	if !found || !strings.HasPrefix(title, "# ") {
+		return note + "\n\n" + translated + "\n"
+	}
+	return title + "\n\n" + note + "\n" + rest + "\n"
+}
+
+// addLanguageFlag adds --lang to a command that generates content
+func addLanguageFlag(cmd *cobra.Command) {
+	cmd.Flags().String("lang", "", "Language of the generated content, e.g. es (default: the project's content_language)")
+}
+
+// ApplyLanguageFlag makes the AI write content in the --lang language, when the command has one
+func ApplyLanguageFlag(cmd *cobra.Command) {
+	if flag := cmd.Flags().Lookup("lang"); flag != nil && flag.Value.String() != "" {
+		ai.SetContentLanguage(flag.Value.String())
+	}
+}
@@ -0,0 +1,193 @@
+package commitmsg
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/baudevs/yolo.baudevs.com/internal/models"
+)
+
+// DefaultTypes are the commit types of the Conventional Commits convention
+var DefaultTypes = []string{"feat", "fix", "docs", "style", "refactor", "perf", "test", "build", "ci", "chore", "revert"}
+
+// headerPattern matches "type(scope)!: subject", after an optional gitmoji
+// and ticket prefix such as "✨ [T012] "
+var headerPattern = regexp.MustCompile(`^(?:(?::[a-z0-9_+-]+:|[^\x00-\x7F]+) )?(?:\[([A-Za-z][A-Za-z0-9]*-?\d+)\] )?([a-zA-Z]+)(?:\(([^()]*)\))?(!)?: (.*)$`)
+
+// footerPattern matches a git trailer such as "Refs: T003", "Fixes #12" or "BREAKING CHANGE: ..."
+var footerPattern = regexp.MustCompile(`^(BREAKING CHANGE|BREAKING-CHANGE|[A-Za-z][A-Za-z0-9-]*)(?:: | (#))(.*)$`)
+
+// Footer is a trailer at the end of a commit message. Issue footers such as
+// "Fixes #12" keep their "#" in the value.
+type Footer struct {
+	Token string
+	Value string
+}
+
+// String renders the footer, e.g. "Refs: T003" or "Fixes #12"
+func (f Footer) String() string {
+	if strings.HasPrefix(f.Value, "#") {
+		return f.Token + " " + f.Value
+	}
+	return f.Token + ": " + f.Value
+}
+
+// Message is a commit message split into its Conventional Commits parts.
+// Breaking is set by "!" after the type or scope, or a BREAKING CHANGE footer;
+// IssueRefs come from a ticket prefix and the Refs, Closes and Fixes footers,
+// CoAuthors from the Co-authored-by footers.
+type Message struct {
+	models.CommitMessage
+	Footers []Footer
+}
+
+// Header returns the first line of the message, e.g. "feat(cli)!: add eval"
+func (m Message) Header() string {
+	header := m.Type
+	if m.Scope != "" {
+		header += "(" + m.Scope + ")"
+	}
+	if m.Breaking {
+		header += "!"
+	}
+	return header + ": " + m.Subject
+}
+
+// Parse splits a commit message in the Conventional Commits format.
+// It fails when the header is not "type(scope): subject".
+func Parse(text string) (Message, error) {
+	text = strings.TrimSpace(strings.ReplaceAll(text, "\r\n", "\n"))
+	header, rest, _ := strings.Cut(text, "\n")
+
+	match := headerPattern.FindStringSubmatch(strings.TrimSpace(header))
+	if match == nil {
+		return Message{}, fmt.Errorf("header %q is not in the form \"type(scope): subject\"", header)
+	}
+	msg := Message{CommitMessage: models.CommitMessage{
+		Type:     match[2],
+		Scope:    strings.TrimSpace(match[3]),
+		Breaking: match[4] == "!",
+		Subject:  strings.TrimSpace(match[5]),
+	}}
+	if match[1] != "" {
+		msg.IssueRefs = append(msg.IssueRefs, match[1])
+	}
+	if msg.Subject == "" {
+		return Message{}, fmt.Errorf("header %q has no subject", header)
+	}
+	if rest != "" && !strings.HasPrefix(rest, "\n") {
+		return Message{}, fmt.Errorf("the header must be followed by a blank line")
+	}
+
+	// Footers are the last paragraph, when every line of it is a trailer
+	paragraphs := strings.Split(strings.TrimSpace(rest), "\n\n")
+	if last := paragraphs[len(paragraphs)-1]; last != "" {
+		if footers, ok := parseFooters(last); ok {
+			msg.Footers = footers
+			paragraphs = paragraphs[:len(paragraphs)-1]
+		}
+	}
+	msg.Body = strings.TrimSpace(strings.Join(paragraphs, "\n\n"))
+
+	for _, f := range msg.Footers {
+		switch strings.ToLower(f.Token) {
+		case "breaking change", "breaking-change":
+			msg.Breaking = true
+			msg.BreakingChange = strings.TrimSpace(f.Value)
+		case "refs", "closes", "fixes":
+			for _, ref := range strings.Split(f.Value, ",") {
+				if ref = strings.TrimSpace(ref); ref != "" && !slices.Contains(msg.IssueRefs, ref) {
+					msg.IssueRefs = append(msg.IssueRefs, ref)
+				}
+			}
+		case "co-authored-by":
+			msg.CoAuthors = append(msg.CoAuthors, strings.TrimSpace(f.Value))
+		}
+	}
+	return msg, nil
+}
+
+// parseFooters parses a paragraph of trailers, continuation lines included
+func parseFooters(paragraph string) ([]Footer, bool) {
+	var footers []Footer
+	for _, line := range strings.Split(paragraph, "\n") {
+		if match := footerPattern.FindStringSubmatch(line); match != nil {
+			footers = append(footers, Footer{Token: match[1], Value: match[2] + match[3]})
+			continue
+		}
+		if len(footers) == 0 || !strings.HasPrefix(line, " ") {
+			return nil, false
+		}
+		footers[len(footers)-1].Value += "\n" + strings.TrimSpace(line)
+	}
+	return footers, true
+}
+
+// AddRefs adds a "Refs:" trailer with the refs missing from the message's
+// IssueRefs, keeping the rest of the text as it is
+func AddRefs(text string, refs ...string) string {
+	var existing []string
+	if msg, err := Parse(text); err == nil {
+		existing = msg.IssueRefs
+	}
+	var missing []string
+	for _, ref := range refs {
+		if !slices.Contains(existing, ref) && !slices.Contains(missing, ref) {
+			missing = append(missing, ref)
+		}
+	}
+	if len(missing) == 0 {
+		return strings.TrimSpace(text)
+	}
+	return AddTrailers(text, Footer{Token: "Refs", Value: strings.Join(missing, ", ")})
+}
+
+// AddTrailers appends trailers the message does not have yet to its last
+// paragraph of trailers, or as a new paragraph, keeping the rest of the text
+func AddTrailers(text string, trailers ...Footer) string {
+	text = strings.TrimSpace(strings.ReplaceAll(text, "\r\n", "\n"))
+	paragraphs := strings.Split(text, "\n\n")
+	existing, ok := parseFooters(paragraphs[len(paragraphs)-1])
+	hasFooters := ok && len(paragraphs) > 1
+	if !hasFooters {
+		existing = nil
+	}
+
+	var lines []string
+	for _, t := range trailers {
+		if t.Value = strings.TrimSpace(t.Value); t.Value == "" || hasFooter(existing, t) {
+			continue
+		}
+		existing = append(existing, t)
+		lines = append(lines, t.String())
+	}
+	if len(lines) == 0 {
+		return text
+	}
+	if hasFooters {
+		return text + "\n" + strings.Join(lines, "\n")
+	}
+	return text + "\n\n" + strings.Join(lines, "\n")
+}
+
+// hasFooter reports whether the footers include t, ignoring case
+func hasFooter(footers []Footer, t Footer) bool {
+	for _, f := range footers {
+		if strings.EqualFold(f.Token, t.Token) && strings.EqualFold(strings.TrimSpace(f.Value), t.Value) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsType reports whether typ is one of the allowed types
+func IsType(typ string, allowed []string) bool {
+	for _, a := range allowed {
+		if strings.EqualFold(a, typ) {
+			return true
+		}
+	}
+	return false
+}
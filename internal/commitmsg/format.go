@@ -0,0 +1,200 @@
+package commitmsg
+
+import (
+	"bytes"
+	"fmt"
+	"slices"
+	"strings"
+	"text/template"
+
+	"github.com/baudevs/yolo.baudevs.com/internal/models"
+)
+
+// Message formats
+const (
+	FormatConventional = "conventional" // feat(cli)!: subject
+	FormatGitmoji      = "gitmoji"      // ✨ feat(cli): subject
+	FormatAngular      = "angular"      // [T012] feat(cli): subject
+	FormatTemplate     = "template"     // The project's own text/template
+)
+
+// Formats lists the message formats a project can choose
+var Formats = []string{FormatConventional, FormatGitmoji, FormatAngular, FormatTemplate}
+
+// gitmojis are the gitmoji of each commit type
+var gitmojis = map[string]string{
+	"feat":     "✨",
+	"fix":      "🐛",
+	"docs":     "📝",
+	"style":    "🎨",
+	"refactor": "♻️",
+	"perf":     "⚡️",
+	"test":     "✅",
+	"build":    "📦️",
+	"ci":       "👷",
+	"chore":    "🔧",
+	"revert":   "⏪️",
+}
+
+// breakingGitmoji marks breaking changes, whatever their type
+const breakingGitmoji = "💥"
+
+// Formatter renders a commit message as text
+type Formatter interface {
+	Format(msg Message) (string, error)
+}
+
+// FormatterFunc adapts a function to the Formatter interface
+type FormatterFunc func(msg Message) (string, error)
+
+// Format calls f(msg)
+func (f FormatterFunc) Format(msg Message) (string, error) {
+	return f(msg)
+}
+
+// NewFormatter returns the formatter of a format, "" meaning conventional.
+// The template format renders tmpl with a FormatData.
+func NewFormatter(format, tmpl string) (Formatter, error) {
+	switch strings.ToLower(strings.TrimSpace(format)) {
+	case "", FormatConventional:
+		return FormatterFunc(formatConventional), nil
+	case FormatGitmoji:
+		return FormatterFunc(formatGitmoji), nil
+	case FormatAngular:
+		return FormatterFunc(formatAngular), nil
+	case FormatTemplate:
+		if strings.TrimSpace(tmpl) == "" {
+			return nil, fmt.Errorf("the template commit format needs a commit.template")
+		}
+		t, err := template.New("commit").Funcs(template.FuncMap{
+			"join":  strings.Join,
+			"lower": strings.ToLower,
+			"upper": strings.ToUpper,
+		}).Option("missingkey=error").Parse(tmpl)
+		if err != nil {
+			return nil, fmt.Errorf("invalid commit.template: %w", err)
+		}
+		return templateFormatter{t}, nil
+	}
+	return nil, fmt.Errorf("unknown commit format %q, use one of: %s", format, strings.Join(Formats, ", "))
+}
+
+// FormatData is what a commit.template is rendered with: the message fields,
+// plus its gitmoji, ticket (the first issue ref), conventional header and
+// trailers paragraph
+type FormatData struct {
+	models.CommitMessage
+	Footers  []Footer
+	Emoji    string
+	Ticket   string
+	Header   string
+	Trailers string
+}
+
+type templateFormatter struct {
+	tmpl *template.Template
+}
+
+func (f templateFormatter) Format(msg Message) (string, error) {
+	data := FormatData{
+		CommitMessage: msg.CommitMessage,
+		Footers:       msg.Footers,
+		Emoji:         emoji(msg),
+		Header:        msg.Header(),
+		Trailers:      trailers(msg, true),
+	}
+	if len(msg.IssueRefs) > 0 {
+		data.Ticket = msg.IssueRefs[0]
+	}
+
+	var buf bytes.Buffer
+	if err := f.tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("failed to render commit.template: %w", err)
+	}
+	return strings.TrimSpace(buf.String()), nil
+}
+
+func formatConventional(msg Message) (string, error) {
+	return join(msg.Header(), msg.Body, trailers(msg, true)), nil
+}
+
+func formatGitmoji(msg Message) (string, error) {
+	return join(emoji(msg)+" "+msg.Header(), msg.Body, trailers(msg, true)), nil
+}
+
+// formatAngular prefixes the header with the ticket, the first issue ref.
+// Breaking changes are only marked in the header when they have no description.
+func formatAngular(msg Message) (string, error) {
+	header := msg
+	header.Breaking = msg.Breaking && strings.TrimSpace(msg.BreakingChange) == ""
+	text := header.Header()
+	refs := true
+	if len(msg.IssueRefs) > 0 {
+		text = "[" + msg.IssueRefs[0] + "] " + text
+		refs = len(msg.IssueRefs) > 1 // A single ref is already the ticket
+	}
+	return join(text, msg.Body, trailers(msg, refs)), nil
+}
+
+// emoji returns the gitmoji of the message's type
+func emoji(msg Message) string {
+	if msg.Breaking {
+		return breakingGitmoji
+	}
+	if e, ok := gitmojis[strings.ToLower(msg.Type)]; ok {
+		return e
+	}
+	return "🔨"
+}
+
+// trailers renders the footers paragraph: the breaking change description,
+// the issue refs no footer mentions yet, co-authors, then the other footers
+func trailers(msg Message, refs bool) string {
+	var lines []string
+	if desc := strings.TrimSpace(msg.BreakingChange); msg.Breaking && desc != "" {
+		lines = append(lines, "BREAKING CHANGE: "+desc)
+	}
+
+	var mentioned, others []string
+	for _, f := range msg.Footers {
+		switch strings.ToLower(f.Token) {
+		case "breaking change", "breaking-change", "co-authored-by":
+			continue // Rendered from the message fields
+		case "refs", "closes", "fixes":
+			mentioned = append(mentioned, strings.Split(f.Value, ",")...)
+		}
+		others = append(others, f.String())
+	}
+	var missing []string
+	for _, ref := range msg.IssueRefs {
+		if !slices.Contains(trimAll(mentioned), ref) {
+			missing = append(missing, ref)
+		}
+	}
+	if refs && len(missing) > 0 {
+		lines = append(lines, "Refs: "+strings.Join(missing, ", "))
+	}
+
+	for _, author := range msg.CoAuthors {
+		lines = append(lines, "Co-authored-by: "+strings.TrimSpace(author))
+	}
+	return strings.Join(append(lines, others...), "\n")
+}
+
+func trimAll(values []string) []string {
+	for i := range values {
+		values[i] = strings.TrimSpace(values[i])
+	}
+	return values
+}
+
+// join puts the non-empty parts of a message together as paragraphs
+func join(parts ...string) string {
+	var paragraphs []string
+	for _, p := range parts {
+		if p = strings.TrimSpace(p); p != "" {
+			paragraphs = append(paragraphs, p)
+		}
+	}
+	return strings.Join(paragraphs, "\n\n")
+}
@@ -0,0 +1,167 @@
+package commitmsg
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// Problem levels: errors reject the message, warnings only report
+const (
+	LevelError   = "error"
+	LevelWarning = "warning"
+)
+
+// DefaultMaxSubjectLength is the longest first line when the rules set none
+const DefaultMaxSubjectLength = 72
+
+// scissors is the line below which git ignores the rest of the message
+const scissors = "# ------------------------ >8 ------------------------"
+
+// Rules are the conventions a commit message is linted against
+type Rules struct {
+	Types            []string // Allowed types (default: DefaultTypes)
+	Scopes           []string // Allowed scopes (default: any)
+	RequireScope     bool
+	MaxSubjectLength int // Default: DefaultMaxSubjectLength
+}
+
+// Problem is one rule a message breaks
+type Problem struct {
+	Level   string
+	Message string
+}
+
+func (p Problem) String() string {
+	return p.Level + ": " + p.Message
+}
+
+// knownFooter matches lines meant as footers, to catch malformed ones
+var knownFooter = regexp.MustCompile(`(?i)^(breaking[ -]change|refs|closes|fixes|co-authored-by|signed-off-by)\b`)
+
+// identity matches "Name <email>"
+var identity = regexp.MustCompile(`^[^<>]+ <[^<>\s]+@[^<>\s]+>$`)
+
+// exemptPrefixes start messages git writes itself, which are not linted
+var exemptPrefixes = []string{"Merge ", "Revert \"", "fixup! ", "squash! ", "amend! "}
+
+// StripComments removes what git drops from a message being edited: comment
+// lines and everything below the scissors line
+func StripComments(text string) string {
+	var lines []string
+	for _, line := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n") {
+		if line == scissors {
+			break
+		}
+		if !strings.HasPrefix(line, "#") {
+			lines = append(lines, strings.TrimRight(line, " \t"))
+		}
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+// Lint checks a message against the rules. Merge, revert, fixup and squash
+// messages written by git are accepted as they are.
+func Lint(text string, rules Rules) []Problem {
+	text = StripComments(text)
+	if text == "" {
+		return []Problem{{LevelError, "the message is empty"}}
+	}
+	if isExempt(text) {
+		return nil
+	}
+
+	msg, err := Parse(text)
+	if err != nil {
+		return []Problem{{LevelError, err.Error()}}
+	}
+
+	var problems []Problem
+	add := func(level, format string, args ...interface{}) {
+		problems = append(problems, Problem{level, fmt.Sprintf(format, args...)})
+	}
+
+	types := rules.Types
+	if len(types) == 0 {
+		types = DefaultTypes
+	}
+	if !IsType(msg.Type, types) {
+		add(LevelError, "type %q is not one of: %s", msg.Type, strings.Join(types, ", "))
+	}
+
+	switch {
+	case msg.Scope == "" && rules.RequireScope:
+		add(LevelError, "a scope is required, e.g. %s(<scope>): %s", msg.Type, msg.Subject)
+	case msg.Scope != "" && len(rules.Scopes) > 0:
+		for _, scope := range strings.Split(msg.Scope, ",") {
+			if !IsType(strings.TrimSpace(scope), rules.Scopes) {
+				add(LevelError, "scope %q is not one of: %s", strings.TrimSpace(scope), strings.Join(rules.Scopes, ", "))
+			}
+		}
+	}
+
+	limit := rules.MaxSubjectLength
+	if limit <= 0 {
+		limit = DefaultMaxSubjectLength
+	}
+	header, _, _ := strings.Cut(text, "\n")
+	if n := len([]rune(header)); n > limit {
+		add(LevelError, "the first line is %d characters, the limit is %d", n, limit)
+	}
+	if strings.HasSuffix(msg.Subject, ".") {
+		add(LevelWarning, "the subject should not end with a period")
+	}
+	if first := []rune(msg.Subject)[0]; unicode.IsUpper(first) {
+		add(LevelWarning, "the subject should start in lower case")
+	}
+
+	for _, f := range msg.Footers {
+		lower := strings.ToLower(f.Token)
+		switch {
+		case lower == "breaking change" || lower == "breaking-change":
+			if f.Token != strings.ToUpper(f.Token) {
+				add(LevelError, "write %q in upper case", f.Token)
+			}
+			if strings.TrimSpace(f.Value) == "" {
+				add(LevelError, "%s needs a description", f.Token)
+			}
+		case lower == "co-authored-by" || lower == "signed-off-by":
+			if !IsIdentity(f.Value) {
+				add(LevelError, "%s should be \"Name <email>\", got %q", f.Token, f.Value)
+			}
+		}
+	}
+
+	// A footer paragraph that did not parse has a malformed trailer in it
+	if len(msg.Footers) == 0 && msg.Body != "" {
+		paragraphs := strings.Split(msg.Body, "\n\n")
+		for _, line := range strings.Split(paragraphs[len(paragraphs)-1], "\n") {
+			switch {
+			case !knownFooter.MatchString(line) || footerPattern.MatchString(line):
+			case strings.HasPrefix(strings.ToLower(line), "breaking"):
+				add(LevelError, "malformed footer %q, expected \"BREAKING CHANGE: description\"", line)
+			default:
+				add(LevelError, "malformed footer %q, expected \"Token: value\"", line)
+			}
+		}
+	}
+
+	return problems
+}
+
+// IsIdentity reports whether text is a "Name <email>" identity, as trailers such
+// as Co-authored-by expect
+func IsIdentity(text string) bool {
+	return identity.MatchString(strings.TrimSpace(text))
+}
+
+// HasErrors reports whether any problem rejects the message
+func HasErrors(problems []Problem) bool {
+	for _, p := range problems {
+		if p.Level == LevelError {
+			return true
+		}
+	}
+	return false
+}
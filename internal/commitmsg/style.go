@@ -0,0 +1,246 @@
+package commitmsg
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/baudevs/yolo.baudevs.com/internal/git"
+	"gopkg.in/yaml.v3"
+)
+
+// StylePath is where the learned commit style is cached, relative to the project root
+var StylePath = filepath.Join("yolo", "settings", "commit_style.yml")
+
+// StyleMaxAge is how long a cached style is used before it is learned again
+const StyleMaxAge = 7 * 24 * time.Hour
+
+// styleHistory is the number of recent commits a style is learned from
+const styleHistory = 300
+
+// maxExamples is the number of example subjects kept in a style
+const maxExamples = 6
+
+// Style is how a repository writes its commit messages, learned from its history
+type Style struct {
+	LearnedAt     time.Time      `yaml:"learned_at"`
+	Commits       int            `yaml:"commits"`      // Messages analyzed
+	Conventional  int            `yaml:"conventional"` // Of which in the Conventional Commits format
+	Types         map[string]int `yaml:"types,omitempty"`
+	Scopes        map[string]int `yaml:"scopes,omitempty"`
+	Trailers      map[string]int `yaml:"trailers,omitempty"`
+	Lowercase     int            `yaml:"lowercase"` // Subjects starting in lower case
+	Period        int            `yaml:"period"`    // Subjects ending with a period
+	Bodies        int            `yaml:"bodies"`    // Messages with a body
+	SubjectLength int            `yaml:"subject_length"`
+	Examples      []string       `yaml:"examples,omitempty"`
+}
+
+// LearnStyle analyzes commit messages, most recent first. Merge, revert and
+// fixup messages written by git are ignored.
+func LearnStyle(messages []string) Style {
+	style := Style{
+		LearnedAt: time.Now(),
+		Types:     make(map[string]int),
+		Scopes:    make(map[string]int),
+		Trailers:  make(map[string]int),
+	}
+
+	var lengths []int
+	var subjects []string
+	typeExample := make(map[string]string)
+
+	for _, text := range messages {
+		text = strings.TrimSpace(text)
+		if text == "" || isExempt(text) {
+			continue
+		}
+		style.Commits++
+
+		header, rest, _ := strings.Cut(text, "\n")
+		header = strings.TrimSpace(header)
+		subjects = append(subjects, header)
+		lengths = append(lengths, len([]rune(header)))
+		if strings.TrimSpace(rest) != "" {
+			style.Bodies++
+		}
+
+		subject := header
+		if msg, err := Parse(text); err == nil {
+			style.Conventional++
+			typ := strings.ToLower(msg.Type)
+			style.Types[typ]++
+			for _, scope := range strings.Split(msg.Scope, ",") {
+				if scope = strings.TrimSpace(scope); scope != "" {
+					style.Scopes[scope]++
+				}
+			}
+			for _, f := range msg.Footers {
+				style.Trailers[f.Token]++
+			}
+			if _, ok := typeExample[typ]; !ok {
+				typeExample[typ] = header
+			}
+			subject = msg.Subject
+		}
+
+		if first := []rune(subject); len(first) > 0 && unicode.IsLower(first[0]) {
+			style.Lowercase++
+		}
+		if strings.HasSuffix(subject, ".") {
+			style.Period++
+		}
+	}
+
+	if len(lengths) > 0 {
+		sort.Ints(lengths)
+		style.SubjectLength = lengths[len(lengths)/2]
+	}
+
+	// One recent example of each type, the most used types first
+	for _, typ := range ranked(style.Types) {
+		if len(style.Examples) == maxExamples {
+			break
+		}
+		style.Examples = append(style.Examples, typeExample[typ])
+	}
+	if style.Conventional*2 < style.Commits {
+		style.Examples = nil
+		for _, subject := range subjects {
+			if len(style.Examples) == maxExamples {
+				break
+			}
+			style.Examples = append(style.Examples, subject)
+		}
+	}
+
+	return style
+}
+
+// isExempt reports whether git wrote the message itself
+func isExempt(text string) bool {
+	for _, prefix := range exemptPrefixes {
+		if strings.HasPrefix(text, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// HasScope reports whether a commit of the history used the scope
+func (s Style) HasScope(scope string) bool {
+	_, ok := s.Scopes[scope]
+	return ok
+}
+
+// Conventions describes the style as a list of rules for a prompt
+func (s Style) Conventions() string {
+	if s.Commits == 0 {
+		return ""
+	}
+	percent := func(n int) int { return n * 100 / s.Commits }
+
+	var lines []string
+	lines = append(lines, fmt.Sprintf("- %d%% of the messages follow the Conventional Commits format", percent(s.Conventional)))
+	if len(s.Types) > 0 {
+		lines = append(lines, "- Types used: "+counts(s.Types, 0))
+	}
+	if len(s.Scopes) > 0 {
+		lines = append(lines, "- Scopes used: "+counts(s.Scopes, 30)+". Prefer one of these scopes")
+	} else if s.Conventional > 0 {
+		lines = append(lines, "- Scopes are not used")
+	}
+
+	casing := "upper case"
+	if s.Lowercase*2 >= s.Commits {
+		casing = "lower case"
+	}
+	period := "without"
+	if s.Period*2 > s.Commits {
+		period = "with"
+	}
+	lines = append(lines, fmt.Sprintf("- Subjects start in %s, end %s a period and are about %d characters long", casing, period, s.SubjectLength))
+	lines = append(lines, fmt.Sprintf("- %d%% of the messages have a body", percent(s.Bodies)))
+	if len(s.Trailers) > 0 {
+		lines = append(lines, "- Trailers used: "+counts(s.Trailers, 0))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// ExampleList lists the example subjects for a prompt
+func (s Style) ExampleList() string {
+	if len(s.Examples) == 0 {
+		return ""
+	}
+	return "- " + strings.Join(s.Examples, "\n- ")
+}
+
+// counts renders "name (n)" pairs, most used first, keeping at most limit (0 for all)
+func counts(m map[string]int, limit int) string {
+	names := ranked(m)
+	if limit > 0 && len(names) > limit {
+		names = names[:limit]
+	}
+	parts := make([]string, len(names))
+	for i, name := range names {
+		parts[i] = fmt.Sprintf("%s (%d)", name, m[name])
+	}
+	return strings.Join(parts, ", ")
+}
+
+// ranked returns the keys of m, the highest counts first
+func ranked(m map[string]int) []string {
+	names := make([]string, 0, len(m))
+	for name := range m {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if m[names[i]] != m[names[j]] {
+			return m[names[i]] > m[names[j]]
+		}
+		return names[i] < names[j]
+	})
+	return names
+}
+
+// LoadStyle returns the repository's commit style: the cached one while it
+// is recent, or else one learned from the last commits and cached again.
+// The cache is only written in yolo projects.
+func LoadStyle(refresh bool) (Style, error) {
+	if !refresh {
+		if data, err := os.ReadFile(StylePath); err == nil {
+			var style Style
+			if err := yaml.Unmarshal(data, &style); err != nil {
+				return Style{}, fmt.Errorf("failed to parse %s: %w", StylePath, err)
+			}
+			if time.Since(style.LearnedAt) < StyleMaxAge {
+				return style, nil
+			}
+		}
+	}
+
+	// An unborn branch has no history to learn from
+	if _, err := git.Run("", "rev-parse", "--verify", "--quiet", "HEAD"); err != nil {
+		return Style{}, nil
+	}
+	output, err := git.Run("", "log", "--no-merges", fmt.Sprintf("-n%d", styleHistory), "--format=%B%x1e")
+	if err != nil {
+		return Style{}, fmt.Errorf("failed to read the commit history: %w", err)
+	}
+	style := LearnStyle(strings.Split(output, "\x1e"))
+
+	if _, err := os.Stat(filepath.Dir(StylePath)); err == nil && style.Commits > 0 {
+		data, err := yaml.Marshal(style)
+		if err != nil {
+			return style, fmt.Errorf("failed to encode the commit style: %w", err)
+		}
+		if err := os.WriteFile(StylePath, data, 0644); err != nil {
+			return style, fmt.Errorf("failed to save %s: %w", StylePath, err)
+		}
+	}
+	return style, nil
+}
@@ -11,6 +11,8 @@ import (
 // Config represents the application configuration
 type Config struct {
 	OpenAI OpenAIConfig `yaml:"openai"`
+	AI     AIConfig     `yaml:"ai,omitempty"`
+	Log    LogConfig    `yaml:"log,omitempty"`
 }
 
 // OpenAIConfig represents OpenAI-specific configuration
@@ -18,6 +20,38 @@ type OpenAIConfig struct {
 	APIKey string `yaml:"api_key"`
 }
 
+// AIConfig represents provider-independent AI configuration
+type AIConfig struct {
+	Provider      string          `yaml:"provider,omitempty"` // "openai" (default) or "mock"
+	Model         string          `yaml:"model,omitempty"`    // Chat model, e.g. "gpt-4o" (default: gpt-4-turbo-preview)
+	Redaction     RedactionConfig `yaml:"redaction,omitempty"`
+	ExplainErrors bool            `yaml:"explain_errors,omitempty"` // Analyze failed git commands with AI
+	Concurrency   int             `yaml:"concurrency,omitempty"`    // Maximum parallel requests when generating many items
+
+	// How structured answers are requested: "functions", "json" or "prompt"
+	// (default: chosen by model)
+	StructuredOutput string `yaml:"structured_output,omitempty"`
+}
+
+// DefaultConcurrency is the number of parallel AI requests when none is configured
+const DefaultConcurrency = 4
+
+// RedactionConfig controls what is masked before anything is sent to the AI
+type RedactionConfig struct {
+	Disabled     bool     `yaml:"disabled,omitempty"`      // Turn off the built-in and custom detectors
+	Patterns     []string `yaml:"patterns,omitempty"`      // Extra regular expressions to mask
+	ExcludePaths []string `yaml:"exclude_paths,omitempty"` // Glob patterns of files never sent
+}
+
+// LogConfig controls the CLI's logs, written to stderr and to a log file
+type LogConfig struct {
+	Level     string `yaml:"level,omitempty"`       // debug, info, warn (default) or error
+	File      string `yaml:"file,omitempty"`        // Log file (default: yolo/logs/yolo.log in the user cache dir), "off" for none
+	MaxSizeMB int    `yaml:"max_size_mb,omitempty"` // Size at which the file is rotated (default: 5)
+	MaxFiles  int    `yaml:"max_files,omitempty"`   // Rotated files kept (default: 3)
+	Prompts   bool   `yaml:"prompts,omitempty"`     // Log the bodies of AI prompts and responses
+}
+
 // LoadConfig loads the configuration from disk
 func LoadConfig() (*Config, error) {
 	configPath, err := GetConfigPath()
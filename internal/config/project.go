@@ -0,0 +1,49 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ProjectConfigPath is the per-project settings file, relative to the project root
+var ProjectConfigPath = filepath.Join("yolo", "settings", "config.yml")
+
+// ProjectSettings are the settings of the current project
+type ProjectSettings struct {
+	ContentLanguage string         `yaml:"content_language,omitempty"` // Language of generated work items, e.g. "es"
+	Commit          CommitSettings `yaml:"commit,omitempty"`
+}
+
+// CommitSettings are the rules the project's commit messages follow
+type CommitSettings struct {
+	Types            []string `yaml:"types,omitempty"`              // Allowed types (default: the Conventional Commits types)
+	Scopes           []string `yaml:"scopes,omitempty"`             // Allowed scopes (default: any)
+	RequireScope     bool     `yaml:"require_scope,omitempty"`      // Reject messages without a scope
+	MaxSubjectLength int      `yaml:"max_subject_length,omitempty"` // Longest first line (default: 72)
+	Format           string   `yaml:"format,omitempty"`             // conventional (default), gitmoji, angular or template
+	Template         string   `yaml:"template,omitempty"`           // text/template of the template format
+	SignOff          bool     `yaml:"signoff,omitempty"`            // Add a Signed-off-by trailer to every commit
+}
+
+// LoadProjectSettings reads the current project's settings.
+// A project without a settings file gets the defaults.
+func LoadProjectSettings() (*ProjectSettings, error) {
+	var settings ProjectSettings
+
+	data, err := os.ReadFile(ProjectConfigPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &settings, nil
+		}
+		return nil, fmt.Errorf("failed to read project settings: %w", err)
+	}
+
+	if err := yaml.Unmarshal(data, &settings); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", ProjectConfigPath, err)
+	}
+
+	return &settings, nil
+}
@@ -0,0 +1,83 @@
+package eval
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// BaselinePath is where the baseline report is saved, relative to the project root
+var BaselinePath = filepath.Join(ProjectSuitesDir, "baseline.json")
+
+// Regression is a case that scores lower than in the baseline
+type Regression struct {
+	Suite  string   `json:"suite"`
+	Case   string   `json:"case"`
+	Before float64  `json:"before"`
+	After  float64  `json:"after"`
+	Failed []string `json:"failed,omitempty"` // Checks that passed in the baseline
+	Error  string   `json:"error,omitempty"`
+}
+
+// SaveBaseline writes a report to compare later runs against
+func SaveBaseline(path string, report Report) error {
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal baseline: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create baseline directory: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write baseline: %w", err)
+	}
+	return nil
+}
+
+// LoadBaseline reads a saved report
+func LoadBaseline(path string) (*Report, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var report Report
+	if err := json.Unmarshal(data, &report); err != nil {
+		return nil, fmt.Errorf("failed to parse baseline %s: %w", path, err)
+	}
+	return &report, nil
+}
+
+// Compare returns the cases whose score dropped by more than tolerance
+// since the baseline. Cases missing from either report are ignored.
+func Compare(baseline, current Report, tolerance float64) []Regression {
+	before := make(map[string]CaseResult)
+	for _, s := range baseline.Suites {
+		for _, c := range s.Cases {
+			before[s.Name+"/"+c.Name] = c
+		}
+	}
+
+	var regressions []Regression
+	for _, s := range current.Suites {
+		for _, c := range s.Cases {
+			old, ok := before[s.Name+"/"+c.Name]
+			if !ok || old.Score-c.Score <= tolerance {
+				continue
+			}
+
+			passed := make(map[string]bool, len(old.Checks))
+			for _, r := range old.Checks {
+				passed[r.Check] = r.Passed
+			}
+			regression := Regression{Suite: s.Name, Case: c.Name, Before: old.Score, After: c.Score, Error: c.Error}
+			for _, r := range c.Checks {
+				if !r.Passed && passed[r.Check] {
+					regression.Failed = append(regression.Failed, r.Check)
+				}
+			}
+			regressions = append(regressions, regression)
+		}
+	}
+	return regressions
+}
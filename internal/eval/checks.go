@@ -0,0 +1,170 @@
+package eval
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/baudevs/yolo.baudevs.com/internal/commitmsg"
+)
+
+// Check types
+const (
+	CheckConventionalCommit = "conventional_commit" // Header is "type(scope): subject" with a known type
+	CheckSubjectLength      = "subject_length"      // First line is at most Max characters
+	CheckChildren           = "children"            // Between Min and Max non-empty lines
+	CheckSections           = "sections"            // Every one of Sections appears in a heading
+	CheckMatches            = "matches"             // Output matches Pattern
+	CheckNotMatches         = "not_matches"         // Output does not match Pattern
+)
+
+// defaultSubjectLength is the subject limit when a check sets none
+const defaultSubjectLength = 72
+
+// Check is a rule an output is scored against
+type Check struct {
+	Type     string   `yaml:"type" json:"type"`
+	Min      int      `yaml:"min,omitempty" json:"min,omitempty"`
+	Max      int      `yaml:"max,omitempty" json:"max,omitempty"`
+	Sections []string `yaml:"sections,omitempty" json:"sections,omitempty"`
+	Pattern  string   `yaml:"pattern,omitempty" json:"pattern,omitempty"`
+	Types    []string `yaml:"types,omitempty" json:"types,omitempty"` // Allowed commit types (default: the Conventional Commits types)
+}
+
+// CheckResult is the outcome of one check
+type CheckResult struct {
+	Check  string `json:"check"`
+	Passed bool   `json:"passed"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// Name identifies the check in reports and baselines, e.g. "children 3-5"
+func (c Check) Name() string {
+	switch c.Type {
+	case CheckSubjectLength:
+		return fmt.Sprintf("%s <= %d", c.Type, c.maxSubject())
+	case CheckChildren:
+		if c.Max > 0 {
+			return fmt.Sprintf("%s %d-%d", c.Type, c.Min, c.Max)
+		}
+		return fmt.Sprintf("%s >= %d", c.Type, c.Min)
+	case CheckSections:
+		return fmt.Sprintf("%s %s", c.Type, strings.Join(c.Sections, ", "))
+	case CheckMatches, CheckNotMatches:
+		return fmt.Sprintf("%s /%s/", c.Type, c.Pattern)
+	}
+	return c.Type
+}
+
+func (c Check) validate() error {
+	switch c.Type {
+	case CheckConventionalCommit, CheckSubjectLength, CheckChildren:
+	case CheckSections:
+		if len(c.Sections) == 0 {
+			return fmt.Errorf("%s check needs sections", c.Type)
+		}
+	case CheckMatches, CheckNotMatches:
+		if _, err := regexp.Compile(c.Pattern); err != nil || c.Pattern == "" {
+			return fmt.Errorf("%s check needs a valid pattern", c.Type)
+		}
+	default:
+		return fmt.Errorf("unknown check type %q", c.Type)
+	}
+	return nil
+}
+
+// Run scores an output against the check
+func (c Check) Run(output string) CheckResult {
+	passed, detail := c.run(strings.TrimSpace(output))
+	return CheckResult{Check: c.Name(), Passed: passed, Detail: detail}
+}
+
+func (c Check) run(output string) (bool, string) {
+	switch c.Type {
+	case CheckConventionalCommit:
+		msg, err := commitmsg.Parse(output)
+		if err != nil {
+			return false, err.Error()
+		}
+		types := c.Types
+		if len(types) == 0 {
+			types = commitmsg.DefaultTypes
+		}
+		if !commitmsg.IsType(msg.Type, types) {
+			return false, fmt.Sprintf("unknown type %q", msg.Type)
+		}
+		return true, ""
+
+	case CheckSubjectLength:
+		subject, _, _ := strings.Cut(output, "\n")
+		if n := len([]rune(subject)); n > c.maxSubject() {
+			return false, fmt.Sprintf("subject is %d characters", n)
+		}
+		return true, ""
+
+	case CheckChildren:
+		n := len(nonEmptyLines(output))
+		if n < c.Min || (c.Max > 0 && n > c.Max) {
+			return false, fmt.Sprintf("got %d", n)
+		}
+		return true, ""
+
+	case CheckSections:
+		var missing []string
+		for _, section := range c.Sections {
+			if !hasSection(output, section) {
+				missing = append(missing, section)
+			}
+		}
+		if len(missing) > 0 {
+			return false, "missing " + strings.Join(missing, ", ")
+		}
+		return true, ""
+
+	case CheckMatches:
+		if !regexp.MustCompile(c.Pattern).MatchString(output) {
+			return false, "no match"
+		}
+		return true, ""
+
+	case CheckNotMatches:
+		if m := regexp.MustCompile(c.Pattern).FindString(output); m != "" {
+			return false, fmt.Sprintf("found %q", m)
+		}
+		return true, ""
+	}
+	return false, fmt.Sprintf("unknown check type %q", c.Type)
+}
+
+func (c Check) maxSubject() int {
+	if c.Max > 0 {
+		return c.Max
+	}
+	return defaultSubjectLength
+}
+
+func nonEmptyLines(text string) []string {
+	var lines []string
+	for _, line := range strings.Split(text, "\n") {
+		if strings.TrimSpace(line) != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+// headingPattern matches lines that introduce a section: markdown headings,
+// bold or numbered lines, and short lines ending with a colon
+var headingPattern = regexp.MustCompile(`^(#{1,6}\s|\*\*|\d+\.\s|[^.]{1,60}:$)`)
+
+// hasSection reports whether a heading of the text mentions the section
+func hasSection(text, section string) bool {
+	section = strings.ToLower(section)
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if headingPattern.MatchString(line) && strings.Contains(strings.ToLower(line), section) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,146 @@
+package eval
+
+import (
+	"context"
+	"time"
+
+	"github.com/baudevs/yolo.baudevs.com/internal/ai"
+	"github.com/baudevs/yolo.baudevs.com/internal/utils"
+)
+
+// Report is the outcome of an eval run
+type Report struct {
+	Model  string        `json:"model"`
+	Time   time.Time     `json:"time"`
+	Suites []SuiteResult `json:"suites"`
+}
+
+// SuiteResult is the outcome of one suite, its score the mean of its cases
+type SuiteResult struct {
+	Name     string       `json:"name"`
+	Prompt   string       `json:"prompt,omitempty"`
+	Pipeline string       `json:"pipeline,omitempty"`
+	Score    float64      `json:"score"`
+	Cases    []CaseResult `json:"cases"`
+}
+
+// Pipeline generates the output of a case from its data, the way a command
+// does with several prompts
+type Pipeline func(ctx context.Context, client *ai.Client, data map[string]string) (string, error)
+
+// Pipelines are the pipelines suites can run instead of a single prompt
+var Pipelines = map[string]Pipeline{
+	// The messages of yolo commit: the Diff analyzed in chunks and summarized
+	"commit": func(ctx context.Context, client *ai.Client, data map[string]string) (string, error) {
+		message, _, err := ai.NewCommitAI(client).GenerateCommitMessage(ctx, data["Diff"])
+		return message, err
+	},
+}
+
+// CaseResult is the outcome of one case, its score the share of checks passed
+type CaseResult struct {
+	Name   string        `json:"name"`
+	Score  float64       `json:"score"`
+	Checks []CheckResult `json:"checks"`
+	Output string        `json:"output,omitempty"`
+	Error  string        `json:"error,omitempty"`
+}
+
+// Score returns the mean score of all suites
+func (r Report) Score() float64 {
+	if len(r.Suites) == 0 {
+		return 0
+	}
+	var total float64
+	for _, s := range r.Suites {
+		total += s.Score
+	}
+	return total / float64(len(r.Suites))
+}
+
+// Run renders every case of the suites, asks the client, and scores the
+// outputs, running at most concurrency cases at a time. A case whose
+// request fails scores 0; it does not stop the others. Prompts are rendered
+// plain, without the current project's commit style and content language.
+func Run(ctx context.Context, client *ai.Client, suites []Suite, concurrency int, progress func(suite, c string)) Report {
+	type job struct{ suite, c int }
+
+	report := Report{Model: client.Model(), Time: time.Now().UTC()}
+	var jobs []job
+	for i, suite := range suites {
+		report.Suites = append(report.Suites, SuiteResult{
+			Name:     suite.Name,
+			Prompt:   suite.Prompt,
+			Pipeline: suite.Pipeline,
+			Cases:    make([]CaseResult, len(suite.Cases)),
+		})
+		for j := range suite.Cases {
+			jobs = append(jobs, job{i, j})
+		}
+	}
+
+	ai.SetPlainPrompts(true)
+	defer ai.SetPlainPrompts(false)
+
+	utils.ForEach(ctx, len(jobs), concurrency, func(ctx context.Context, n int) error {
+		suite := suites[jobs[n].suite]
+		c := suite.Cases[jobs[n].c]
+		report.Suites[jobs[n].suite].Cases[jobs[n].c] = runCase(ctx, client, suite, c)
+		if progress != nil {
+			progress(suite.Name, c.Name)
+		}
+		return nil
+	})
+
+	for i := range report.Suites {
+		var total float64
+		for _, c := range report.Suites[i].Cases {
+			total += c.Score
+		}
+		report.Suites[i].Score = total / float64(len(report.Suites[i].Cases))
+	}
+	return report
+}
+
+func runCase(ctx context.Context, client *ai.Client, suite Suite, c Case) CaseResult {
+	result := CaseResult{Name: c.Name}
+
+	output, err := caseOutput(ctx, client, suite, c)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	result.Output = output
+
+	checks := append(append([]Check{}, suite.Checks...), c.Checks...)
+	passed := 0
+	for _, check := range checks {
+		r := check.Run(output)
+		if r.Passed {
+			passed++
+		}
+		result.Checks = append(result.Checks, r)
+	}
+	result.Score = 1
+	if len(checks) > 0 {
+		result.Score = float64(passed) / float64(len(checks))
+	}
+	return result
+}
+
+// caseOutput runs the suite's pipeline on the case, or asks its prompt
+func caseOutput(ctx context.Context, client *ai.Client, suite Suite, c Case) (string, error) {
+	if suite.Pipeline != "" {
+		return Pipelines[suite.Pipeline](ctx, client, c.Data)
+	}
+
+	data := make(ai.PromptData, len(c.Data))
+	for k, v := range c.Data {
+		data[k] = v
+	}
+	prompt, err := ai.RenderPrompt(suite.Prompt, data)
+	if err != nil {
+		return "", err
+	}
+	return client.Ask(ctx, prompt)
+}
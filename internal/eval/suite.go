@@ -0,0 +1,140 @@
+package eval
+
+import (
+	"embed"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ProjectSuitesDir holds the project's own suites, relative to the project root
+var ProjectSuitesDir = filepath.Join("yolo", "eval")
+
+//go:embed suites/*.yml
+var defaultSuites embed.FS
+
+// Suite is a set of cases run against one prompt, or one pipeline
+type Suite struct {
+	Name        string  `yaml:"name"`
+	Description string  `yaml:"description,omitempty"`
+	Prompt      string  `yaml:"prompt,omitempty"`   // Name of the prompt in the registry
+	Pipeline    string  `yaml:"pipeline,omitempty"` // Instead of a prompt, a pipeline of several prompts (see Pipelines)
+	Checks      []Check `yaml:"checks,omitempty"`   // Run on the output of every case
+	Cases       []Case  `yaml:"cases"`
+	Source      string  `yaml:"-"`
+}
+
+// Case is one input of a suite
+type Case struct {
+	Name   string            `yaml:"name"`
+	Data   map[string]string `yaml:"data"`             // Values the prompt is rendered with, or the pipeline's input
+	Checks []Check           `yaml:"checks,omitempty"` // Run after the suite's checks
+}
+
+// LoadSuites returns the built-in suites and the project's suites in
+// yolo/eval. A project suite replaces the built-in suite of the same name.
+func LoadSuites() ([]Suite, error) {
+	byName := make(map[string]Suite)
+
+	entries, err := defaultSuites.ReadDir("suites")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read built-in suites: %w", err)
+	}
+	for _, entry := range entries {
+		data, err := defaultSuites.ReadFile("suites/" + entry.Name())
+		if err != nil {
+			return nil, fmt.Errorf("failed to read built-in suite %s: %w", entry.Name(), err)
+		}
+		suite, err := parseSuite(data, "built-in")
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse built-in suite %s: %w", entry.Name(), err)
+		}
+		byName[suite.Name] = suite
+	}
+
+	files, err := filepath.Glob(filepath.Join(ProjectSuitesDir, "*.yml"))
+	if err != nil {
+		return nil, err
+	}
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read suite: %w", err)
+		}
+		suite, err := parseSuite(data, file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", file, err)
+		}
+		byName[suite.Name] = suite
+	}
+
+	suites := make([]Suite, 0, len(byName))
+	for _, suite := range byName {
+		suites = append(suites, suite)
+	}
+	sort.Slice(suites, func(i, j int) bool { return suites[i].Name < suites[j].Name })
+	return suites, nil
+}
+
+// Target describes what the suite runs: its prompt, or its pipeline
+func (s Suite) Target() string {
+	if s.Pipeline != "" {
+		return s.Pipeline + " pipeline"
+	}
+	return s.Prompt
+}
+
+// Select returns the suites with the given names, or all of them when none is given
+func Select(suites []Suite, names []string) ([]Suite, error) {
+	if len(names) == 0 {
+		return suites, nil
+	}
+
+	var selected []Suite
+	for _, name := range names {
+		found := false
+		for _, suite := range suites {
+			if strings.EqualFold(suite.Name, name) {
+				selected = append(selected, suite)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("unknown eval suite %q", name)
+		}
+	}
+	return selected, nil
+}
+
+func parseSuite(data []byte, source string) (Suite, error) {
+	var suite Suite
+	if err := yaml.Unmarshal(data, &suite); err != nil {
+		return Suite{}, err
+	}
+	if suite.Name == "" || (suite.Prompt == "") == (suite.Pipeline == "") {
+		return Suite{}, fmt.Errorf("a suite needs a name and either a prompt or a pipeline")
+	}
+	if _, ok := Pipelines[suite.Pipeline]; suite.Pipeline != "" && !ok {
+		return Suite{}, fmt.Errorf("suite %s: unknown pipeline %q", suite.Name, suite.Pipeline)
+	}
+	if len(suite.Cases) == 0 {
+		return Suite{}, fmt.Errorf("suite %s has no cases", suite.Name)
+	}
+	for i, c := range suite.Cases {
+		if c.Name == "" {
+			return Suite{}, fmt.Errorf("case %d of suite %s has no name", i+1, suite.Name)
+		}
+		for _, check := range append(suite.Checks, c.Checks...) {
+			if err := check.validate(); err != nil {
+				return Suite{}, fmt.Errorf("case %s of suite %s: %w", c.Name, suite.Name, err)
+			}
+		}
+	}
+	suite.Source = source
+	return suite, nil
+}
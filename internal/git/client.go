@@ -72,10 +72,7 @@ func (c *Client) GetChanges() (string, error) {
 
 // StageAll stages all changes
 func (c *Client) StageAll() error {
-	cmd := exec.Command("git", "add", "-A")
-	cmd.Dir = c.workingDir
-
-	if err := cmd.Run(); err != nil {
+	if _, err := Run(c.workingDir, "add", "-A"); err != nil {
 		return fmt.Errorf("failed to stage changes: %w", err)
 	}
 
@@ -97,10 +94,7 @@ func (c *Client) GetStagedDiff() (string, error) {
 
 // Commit creates a new commit with the given message
 func (c *Client) Commit(message string) error {
-	cmd := exec.Command("git", "commit", "-m", message)
-	cmd.Dir = c.workingDir
-
-	if err := cmd.Run(); err != nil {
+	if _, err := Run(c.workingDir, "commit", "-m", message); err != nil {
 		return fmt.Errorf("failed to commit: %w", err)
 	}
 
@@ -120,12 +114,10 @@ func (c *Client) HasRemote() bool {
 	return len(out) > 0
 }
 
-// Pull pulls changes from remote
+// Pull rebases the current branch on its upstream. Uncommitted changes are
+// stashed during the rebase and applied again afterwards.
 func (c *Client) Pull() error {
-	cmd := exec.Command("git", "pull", "--rebase")
-	cmd.Dir = c.workingDir
-
-	if err := cmd.Run(); err != nil {
+	if _, err := Run(c.workingDir, "pull", "--rebase", "--autostash"); err != nil {
 		return fmt.Errorf("failed to pull: %w", err)
 	}
 
@@ -134,10 +126,7 @@ func (c *Client) Pull() error {
 
 // Push pushes changes to remote
 func (c *Client) Push() error {
-	cmd := exec.Command("git", "push")
-	cmd.Dir = c.workingDir
-
-	if err := cmd.Run(); err != nil {
+	if _, err := Run(c.workingDir, "push"); err != nil {
 		return fmt.Errorf("failed to push: %w", err)
 	}
 
@@ -0,0 +1,201 @@
+package git
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// FileDiff is the part of a unified diff that touches one file
+type FileDiff struct {
+	Path    string // New path, or the old one when the file was deleted
+	OldPath string
+	Header  string // Lines from "diff --git" up to the first hunk
+	Hunks   []Hunk
+	Binary  bool
+	Deleted bool
+}
+
+// Hunk is one "@@" section of a file diff
+type Hunk struct {
+	Header   string
+	OldStart int
+	OldLines int
+	NewStart int
+	NewLines int
+	Lines    []string // Diff lines, each starting with ' ', '+', '-' or '\'
+}
+
+var hunkHeader = regexp.MustCompile(`^@@ -(\d+)(?:,(\d+))? \+(\d+)(?:,(\d+))? @@`)
+
+// ParseDiff splits the output of git diff into files and hunks
+func ParseDiff(diff string) []FileDiff {
+	var files []FileDiff
+	var file *FileDiff
+	var hunk *Hunk
+	var header strings.Builder
+
+	flush := func() {
+		if file == nil {
+			return
+		}
+		if hunk != nil {
+			file.Hunks = append(file.Hunks, *hunk)
+			hunk = nil
+		}
+		if file.Header == "" {
+			file.Header = header.String()
+		}
+		files = append(files, *file)
+		file = nil
+	}
+
+	for _, line := range strings.Split(strings.TrimSuffix(diff, "\n"), "\n") {
+		if strings.HasPrefix(line, "diff --git ") {
+			flush()
+			header.Reset()
+			header.WriteString(line + "\n")
+			file = &FileDiff{}
+			if a, b, ok := splitDiffHeader(line); ok {
+				file.OldPath, file.Path = a, b
+			}
+			continue
+		}
+		if file == nil {
+			continue
+		}
+
+		if m := hunkHeader.FindStringSubmatch(line); m != nil {
+			if hunk != nil {
+				file.Hunks = append(file.Hunks, *hunk)
+			} else {
+				file.Header = header.String()
+			}
+			hunk = &Hunk{
+				Header:   line,
+				OldStart: atoi(m[1]),
+				OldLines: atoiDefault(m[2], 1),
+				NewStart: atoi(m[3]),
+				NewLines: atoiDefault(m[4], 1),
+			}
+			continue
+		}
+		if hunk != nil {
+			hunk.Lines = append(hunk.Lines, line)
+			continue
+		}
+
+		header.WriteString(line + "\n")
+		switch {
+		case strings.HasPrefix(line, "--- a/"):
+			file.OldPath = strings.TrimPrefix(line, "--- a/")
+		case strings.HasPrefix(line, "+++ b/"):
+			file.Path = strings.TrimPrefix(line, "+++ b/")
+		case strings.HasPrefix(line, "deleted file mode"):
+			file.Deleted = true
+		case strings.HasPrefix(line, "Binary files ") || line == "GIT binary patch":
+			file.Binary = true
+		}
+	}
+	flush()
+
+	for i := range files {
+		if files[i].Deleted || files[i].Path == "" {
+			files[i].Path = files[i].OldPath
+		}
+	}
+	return files
+}
+
+// String renders the file diff back to unified diff format
+func (f FileDiff) String() string {
+	var sb strings.Builder
+	sb.WriteString(f.Header)
+	for _, h := range f.Hunks {
+		sb.WriteString(h.String())
+	}
+	return sb.String()
+}
+
+// Stats counts the added and deleted lines of the file
+func (f FileDiff) Stats() (added, deleted int) {
+	for _, h := range f.Hunks {
+		for _, line := range h.Lines {
+			switch {
+			case strings.HasPrefix(line, "+"):
+				added++
+			case strings.HasPrefix(line, "-"):
+				deleted++
+			}
+		}
+	}
+	return added, deleted
+}
+
+// String renders the hunk back to unified diff format
+func (h Hunk) String() string {
+	var sb strings.Builder
+	sb.WriteString(h.Header + "\n")
+	for _, line := range h.Lines {
+		sb.WriteString(line + "\n")
+	}
+	return sb.String()
+}
+
+// Annotated renders the hunk with the new line number in front of every
+// line that exists after the change, so findings can point at exact lines
+func (h Hunk) Annotated() string {
+	var sb strings.Builder
+	sb.WriteString(h.Header + "\n")
+	n := h.NewStart
+	for _, line := range h.Lines {
+		if strings.HasPrefix(line, "-") || strings.HasPrefix(line, `\`) {
+			fmt.Fprintf(&sb, "%6s %s\n", "", line)
+			continue
+		}
+		fmt.Fprintf(&sb, "%6d %s\n", n, line)
+		n++
+	}
+	return sb.String()
+}
+
+// Contains reports whether a line of the new file is inside the hunk
+func (h Hunk) Contains(line int) bool {
+	return line >= h.NewStart && line < h.NewStart+h.NewLines
+}
+
+// Diff runs git diff with the given arguments and parses the result
+func (g *GitOps) Diff(args ...string) ([]FileDiff, error) {
+	output, err := Run(g.workingDir, append([]string{"diff", "--no-color", "--no-ext-diff"}, args...)...)
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseDiff(output), nil
+}
+
+// splitDiffHeader extracts both paths from "diff --git a/x b/y"
+func splitDiffHeader(line string) (string, string, bool) {
+	rest := strings.TrimPrefix(line, "diff --git ")
+	if !strings.HasPrefix(rest, "a/") {
+		return "", "", false
+	}
+	i := strings.LastIndex(rest, " b/")
+	if i == -1 {
+		return "", "", false
+	}
+	return rest[2:i], rest[i+3:], true
+}
+
+func atoi(s string) int {
+	n, _ := strconv.Atoi(s)
+	return n
+}
+
+func atoiDefault(s string, def int) int {
+	if s == "" {
+		return def
+	}
+	return atoi(s)
+}
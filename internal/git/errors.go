@@ -0,0 +1,71 @@
+package git
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/baudevs/yolo.baudevs.com/internal/logging"
+)
+
+// CommandError is a failed git command together with what it printed
+type CommandError struct {
+	Args   []string
+	Output string // Standard output followed by standard error
+	Err    error
+}
+
+func (e *CommandError) Error() string {
+	msg := summary(e.Output)
+	if msg == "" {
+		msg = e.Err.Error()
+	}
+	return fmt.Sprintf("git %s failed: %s", e.Args[0], msg)
+}
+
+func (e *CommandError) Unwrap() error {
+	return e.Err
+}
+
+// Command returns the command line that failed
+func (e *CommandError) Command() string {
+	return "git " + strings.Join(e.Args, " ")
+}
+
+// Run runs git in dir and returns its standard output.
+// On failure the error is a *CommandError carrying stdout and stderr.
+func Run(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	start := time.Now()
+	if err := cmd.Run(); err != nil {
+		// Some failures, e.g. "nothing to commit", are only reported on stdout
+		output := strings.TrimSpace(stdout.String() + "\n" + stderr.String())
+		logging.Debug("git command failed", "args", strings.Join(args, " "), "duration", time.Since(start), "output", output)
+		return stdout.String(), &CommandError{Args: args, Output: output, Err: err}
+	}
+	logging.Debug("git command", "args", strings.Join(args, " "), "duration", time.Since(start))
+	return stdout.String(), nil
+}
+
+// summary picks the line of git output that best explains a failure:
+// the first "error:" or "fatal:" line, or else the last line
+func summary(output string) string {
+	last := ""
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "error: ") || strings.HasPrefix(line, "fatal: ") {
+			return line
+		}
+		if line != "" {
+			last = line
+		}
+	}
+	return last
+}
@@ -0,0 +1,74 @@
+package git
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Head returns the commit HEAD points to, or "" on a branch without commits
+func (g *GitOps) Head() (string, error) {
+	output, err := g.runGit("rev-parse", "--verify", "-q", "HEAD")
+	if err != nil {
+		if _, unborn := g.runGit("symbolic-ref", "-q", "HEAD"); unborn == nil {
+			return "", nil
+		}
+		return "", fmt.Errorf("failed to resolve HEAD: %w", err)
+	}
+	return strings.TrimSpace(output), nil
+}
+
+// WriteTree saves the index as a tree object and returns its hash, so the
+// exact staged state can be restored with ReadTree
+func (g *GitOps) WriteTree() (string, error) {
+	output, err := g.runGit("write-tree")
+	if err != nil {
+		return "", fmt.Errorf("failed to save the index: %w", err)
+	}
+	return strings.TrimSpace(output), nil
+}
+
+// ReadTree replaces the index with a tree or commit, or empties it when
+// treeish is "". The working tree is not touched.
+func (g *GitOps) ReadTree(treeish string) error {
+	args := []string{"read-tree", treeish}
+	if treeish == "" {
+		args = []string{"read-tree", "--empty"}
+	}
+	if _, err := g.runGit(args...); err != nil {
+		return fmt.Errorf("failed to restore the index: %w", err)
+	}
+	return nil
+}
+
+// ApplyCached applies a patch to the index only
+func (g *GitOps) ApplyCached(patch string) error {
+	file, err := os.CreateTemp("", "yolo-*.patch")
+	if err != nil {
+		return fmt.Errorf("failed to create patch file: %w", err)
+	}
+	defer os.Remove(file.Name())
+
+	if _, err := file.WriteString(patch); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to write patch file: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to write patch file: %w", err)
+	}
+
+	_, err = g.runGit("apply", "--cached", "--whitespace=nowarn", file.Name())
+	return err
+}
+
+// ResetSoft moves the current branch to commit, keeping the index and working
+// tree. An empty commit makes the branch unborn again.
+func (g *GitOps) ResetSoft(commit string) error {
+	var err error
+	if commit == "" {
+		_, err = g.runGit("update-ref", "-d", "HEAD")
+	} else {
+		_, err = g.runGit("reset", "-q", "--soft", commit)
+	}
+	return err
+}
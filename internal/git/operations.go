@@ -2,7 +2,6 @@ package git
 
 import (
 	"fmt"
-	"os/exec"
 	"strconv"
 	"strings"
 )
@@ -284,15 +283,7 @@ func (g *GitOps) getCurrentBranch() (string, error) {
 }
 
 func (g *GitOps) runGit(args ...string) (string, error) {
-	cmd := exec.Command("git", args...)
-	cmd.Dir = g.workingDir
-
-	output, err := cmd.CombinedOutput()
-	if err != nil {
-		return "", fmt.Errorf("git command failed: %s: %w", string(output), err)
-	}
-
-	return string(output), nil
+	return Run(g.workingDir, args...)
 }
 
 func (g *GitOps) HasRemote() bool {
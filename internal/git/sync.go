@@ -0,0 +1,147 @@
+package git
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// CurrentBranch returns the checked out branch, or "" when HEAD is detached
+func (c *Client) CurrentBranch() string {
+	output, err := Run(c.workingDir, "symbolic-ref", "--quiet", "--short", "HEAD")
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(output)
+}
+
+// Upstream returns the branch the current one tracks, e.g. "origin/main",
+// or "" when it tracks none
+func (c *Client) Upstream() string {
+	output, err := Run(c.workingDir, "rev-parse", "--abbrev-ref", "--symbolic-full-name", "@{upstream}")
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(output)
+}
+
+// DefaultRemote returns the remote new branches are pushed to: origin when
+// it exists, or else the first one
+func (c *Client) DefaultRemote() (string, error) {
+	output, err := Run(c.workingDir, "remote")
+	if err != nil {
+		return "", fmt.Errorf("failed to list remotes: %w", err)
+	}
+	remotes := strings.Fields(output)
+	if len(remotes) == 0 {
+		return "", fmt.Errorf("the repository has no remote")
+	}
+	for _, remote := range remotes {
+		if remote == "origin" {
+			return remote, nil
+		}
+	}
+	return remotes[0], nil
+}
+
+// Fetch updates the remote-tracking branches of the upstream's remote
+func (c *Client) Fetch() error {
+	if _, err := Run(c.workingDir, "fetch", "--quiet"); err != nil {
+		return fmt.Errorf("failed to fetch: %w", err)
+	}
+	return nil
+}
+
+// Divergence counts the commits only HEAD has and those only its upstream has
+func (c *Client) Divergence() (ahead, behind int, err error) {
+	output, err := Run(c.workingDir, "rev-list", "--left-right", "--count", "HEAD...@{upstream}")
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to compare with the upstream: %w", err)
+	}
+	fields := strings.Fields(output)
+	if len(fields) != 2 {
+		return 0, 0, fmt.Errorf("unexpected rev-list output %q", output)
+	}
+	ahead, _ = strconv.Atoi(fields[0])
+	behind, _ = strconv.Atoi(fields[1])
+	return ahead, behind, nil
+}
+
+// UpstreamRewritten reports whether the upstream's tip was once the tip of
+// the current branch but no longer is in its history, as after an amend, a
+// squash or a reset: pushing then needs a force push
+func (c *Client) UpstreamRewritten() bool {
+	branch := c.CurrentBranch()
+	if branch == "" {
+		return false
+	}
+	upstream, err := Run(c.workingDir, "rev-parse", "--verify", "-q", "@{upstream}")
+	if err != nil {
+		return false
+	}
+	if _, err := Run(c.workingDir, "merge-base", "--is-ancestor", "@{upstream}", "HEAD"); err == nil {
+		return false
+	}
+	reflog, err := Run(c.workingDir, "rev-list", "--walk-reflogs", "refs/heads/"+branch)
+	if err != nil {
+		return false
+	}
+	for _, hash := range strings.Fields(reflog) {
+		if hash == strings.TrimSpace(upstream) {
+			return true
+		}
+	}
+	return false
+}
+
+// Conflicts returns the files left with conflicts by a rebase or merge
+func (c *Client) Conflicts() ([]string, error) {
+	output, err := Run(c.workingDir, "diff", "--name-only", "--diff-filter=U")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list conflicts: %w", err)
+	}
+	return strings.Fields(output), nil
+}
+
+// InProgress returns the operation a previous command left unfinished:
+// "rebase", "merge" or "" for none
+func (c *Client) InProgress() string {
+	exists := func(name string) bool {
+		output, err := Run(c.workingDir, "rev-parse", "--git-path", name)
+		if err != nil {
+			return false
+		}
+		path := strings.TrimSpace(output)
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(c.workingDir, path)
+		}
+		_, err = os.Stat(path)
+		return err == nil
+	}
+	switch {
+	case exists("rebase-merge"), exists("rebase-apply"):
+		return "rebase"
+	case exists("MERGE_HEAD"):
+		return "merge"
+	}
+	return ""
+}
+
+// PushUpstream pushes the current branch to remote and tracks it
+func (c *Client) PushUpstream(remote, branch string) error {
+	if _, err := Run(c.workingDir, "push", "--set-upstream", remote, branch); err != nil {
+		return fmt.Errorf("failed to push: %w", err)
+	}
+	return nil
+}
+
+// ForcePush replaces the upstream with the current branch, unless the
+// upstream has commits that were not fetched yet
+func (c *Client) ForcePush() error {
+	if _, err := Run(c.workingDir, "push", "--force-with-lease"); err != nil {
+		return fmt.Errorf("failed to force push: %w", err)
+	}
+	return nil
+}
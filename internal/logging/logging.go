@@ -0,0 +1,229 @@
+// Package logging is the leveled, structured logger shared by the CLI. Records
+// go to stderr and to a rotated log file, with secrets masked and AI prompt
+// and response bodies left out unless they are enabled.
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// Keys of the attributes holding AI prompt and response bodies
+const (
+	PromptKey   = "prompt"
+	ResponseKey = "response"
+)
+
+// DefaultLevel is the level of stderr output when none is configured
+const DefaultLevel = slog.LevelWarn
+
+// Rotation defaults
+const (
+	DefaultMaxSize  = 5 << 20 // Bytes
+	DefaultMaxFiles = 3       // Rotated files kept besides the current one
+)
+
+// Options configure the logger
+type Options struct {
+	Level    slog.Level // Of stderr output; the file also gets Info records when it is higher
+	File     string     // Log file, "" for none
+	MaxSize  int64      // The file is rotated when bigger (default: DefaultMaxSize)
+	MaxFiles int        // Rotated files kept (default: DefaultMaxFiles)
+	Prompts  bool       // Log prompt and response bodies
+	Stderr   io.Writer  // Default: os.Stderr
+}
+
+var (
+	mu     sync.RWMutex
+	logger = slog.New(newHandler(Options{Level: DefaultLevel}, nil))
+)
+
+// Setup replaces the logger. The log file is rotated first when it has grown
+// past its maximum size; failing to open it leaves stderr as the only output.
+func Setup(opts Options) error {
+	var file io.Writer
+	var fileErr error
+	if opts.File != "" {
+		file, fileErr = openLogFile(opts)
+	}
+
+	mu.Lock()
+	logger = slog.New(newHandler(opts, file))
+	mu.Unlock()
+
+	if fileErr != nil {
+		return fmt.Errorf("failed to open log file: %w", fileErr)
+	}
+	return nil
+}
+
+// DefaultFile returns the log file under the user cache directory
+func DefaultFile() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get user cache directory: %w", err)
+	}
+	return filepath.Join(dir, "yolo", "logs", "yolo.log"), nil
+}
+
+// ParseLevel reads a level name: debug, info, warn or error
+func ParseLevel(name string) (slog.Level, error) {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(strings.TrimSpace(name))); err != nil {
+		return DefaultLevel, fmt.Errorf("unknown log level %q, use debug, info, warn or error", name)
+	}
+	return level, nil
+}
+
+// Logger returns the current logger
+func Logger() *slog.Logger {
+	mu.RLock()
+	defer mu.RUnlock()
+	return logger
+}
+
+// Debug logs at debug level, with alternating keys and values
+func Debug(msg string, args ...any) { Logger().Debug(msg, args...) }
+
+// Info logs at info level
+func Info(msg string, args ...any) { Logger().Info(msg, args...) }
+
+// Warn logs at warn level
+func Warn(msg string, args ...any) { Logger().Warn(msg, args...) }
+
+// Error logs at error level
+func Error(msg string, args ...any) { Logger().Error(msg, args...) }
+
+// Prompt is an AI prompt body, only logged when prompts are enabled
+func Prompt(text string) slog.Attr {
+	return slog.String(PromptKey, text)
+}
+
+// Response is an AI response body, only logged when prompts are enabled
+func Response(text string) slog.Attr {
+	return slog.String(ResponseKey, text)
+}
+
+// secretKey matches attribute keys whose values are never logged
+var secretKey = regexp.MustCompile(`(?i)(api[_-]?key|secret|password|authorization|credential|(^|_)token$)`)
+
+// secretValues match secrets inside logged text
+var secretValues = []*regexp.Regexp{
+	regexp.MustCompile(`sk-[A-Za-z0-9_-]{16,}`),
+	regexp.MustCompile(`(?i)bearer\s+[A-Za-z0-9._~+/=-]{8,}`),
+	regexp.MustCompile(`AKIA[0-9A-Z]{16}`),
+	regexp.MustCompile(`gh[pousr]_[A-Za-z0-9]{20,}`),
+	regexp.MustCompile(`(?i)((?:api[_-]?key|token|secret|password)\s*[:=]\s*)["']?[^\s"']{6,}`),
+}
+
+// redact masks secrets in attributes and drops prompt bodies unless enabled
+func redact(prompts bool) func(groups []string, a slog.Attr) slog.Attr {
+	return func(groups []string, a slog.Attr) slog.Attr {
+		if len(groups) == 0 && (a.Key == slog.TimeKey || a.Key == slog.LevelKey || a.Key == slog.MessageKey) {
+			return a
+		}
+		if secretKey.MatchString(a.Key) {
+			return slog.String(a.Key, "[REDACTED]")
+		}
+		if (a.Key == PromptKey || a.Key == ResponseKey) && !prompts {
+			return slog.String(a.Key, fmt.Sprintf("[%d bytes, enable log.prompts to log]", len(a.Value.String())))
+		}
+		if a.Value.Kind() == slog.KindString || a.Value.Kind() == slog.KindAny {
+			text := a.Value.String()
+			masked := maskSecrets(text)
+			if masked != text {
+				return slog.String(a.Key, masked)
+			}
+		}
+		return a
+	}
+}
+
+// maskSecrets replaces the secrets found in text
+func maskSecrets(text string) string {
+	for _, re := range secretValues {
+		text = re.ReplaceAllStringFunc(text, func(match string) string {
+			if sub := re.FindStringSubmatch(match); len(sub) > 1 {
+				return sub[1] + "[REDACTED]"
+			}
+			return "[REDACTED]"
+		})
+	}
+	return text
+}
+
+// newHandler writes text records to stderr at the level, and JSON records
+// of Info and above, or the level when lower, to the file
+func newHandler(opts Options, file io.Writer) slog.Handler {
+	stderr := opts.Stderr
+	if stderr == nil {
+		stderr = os.Stderr
+	}
+	replace := redact(opts.Prompts)
+
+	console := slog.NewTextHandler(stderr, &slog.HandlerOptions{
+		Level: opts.Level,
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if len(groups) == 0 && a.Key == slog.TimeKey {
+				return slog.Attr{} // Noise on a terminal, the file has it
+			}
+			return replace(groups, a)
+		},
+	})
+	if file == nil {
+		return console
+	}
+
+	fileLevel := slog.LevelInfo
+	if opts.Level < fileLevel {
+		fileLevel = opts.Level
+	}
+	return fanout{console, slog.NewJSONHandler(file, &slog.HandlerOptions{Level: fileLevel, ReplaceAttr: replace})}
+}
+
+// fanout sends records to every handler enabled for them
+type fanout []slog.Handler
+
+func (f fanout) Enabled(ctx context.Context, level slog.Level) bool {
+	for _, h := range f {
+		if h.Enabled(ctx, level) {
+			return true
+		}
+	}
+	return false
+}
+
+func (f fanout) Handle(ctx context.Context, r slog.Record) error {
+	var firstErr error
+	for _, h := range f {
+		if h.Enabled(ctx, r.Level) {
+			if err := h.Handle(ctx, r.Clone()); err != nil && firstErr == nil {
+				firstErr = err
+			}
+		}
+	}
+	return firstErr
+}
+
+func (f fanout) WithAttrs(attrs []slog.Attr) slog.Handler {
+	handlers := make(fanout, len(f))
+	for i, h := range f {
+		handlers[i] = h.WithAttrs(attrs)
+	}
+	return handlers
+}
+
+func (f fanout) WithGroup(name string) slog.Handler {
+	handlers := make(fanout, len(f))
+	for i, h := range f {
+		handlers[i] = h.WithGroup(name)
+	}
+	return handlers
+}
@@ -0,0 +1,69 @@
+package logging
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// openLogFile opens the log file for appending, rotating it first when it is
+// bigger than the maximum size. Each run of the CLI is short, so checking once
+// when it starts is enough.
+func openLogFile(opts Options) (*os.File, error) {
+	return OpenRotated(opts.File, opts.MaxSize, opts.MaxFiles)
+}
+
+// OpenRotated opens a file for appending, rotating it first when it is bigger
+// than maxSize: yolo.log becomes yolo.log.1, yolo.log.1 becomes yolo.log.2 and
+// so on, the oldest beyond maxFiles being removed. Zero sizes and counts take
+// the defaults. The file is private to the user.
+func OpenRotated(path string, maxSize int64, maxFiles int) (*os.File, error) {
+	if maxSize <= 0 {
+		maxSize = DefaultMaxSize
+	}
+	if maxFiles <= 0 {
+		maxFiles = DefaultMaxFiles
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, err
+	}
+	if info, err := os.Stat(path); err == nil && info.Size() > maxSize {
+		if err := rotate(path, maxFiles); err != nil {
+			return nil, err
+		}
+	}
+	return os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+}
+
+// RotatedFiles returns the files of a rotated log that exist, oldest first
+// and path itself last
+func RotatedFiles(path string) []string {
+	var files []string
+	for n := 1; ; n++ {
+		numbered := fmt.Sprintf("%s.%d", path, n)
+		if _, err := os.Stat(numbered); err != nil {
+			break
+		}
+		files = append([]string{numbered}, files...)
+	}
+	if _, err := os.Stat(path); err == nil {
+		files = append(files, path)
+	}
+	return files
+}
+
+// rotate shifts the numbered copies of path up by one and moves path to path.1
+func rotate(path string, maxFiles int) error {
+	numbered := func(n int) string { return fmt.Sprintf("%s.%d", path, n) }
+
+	if err := os.Remove(numbered(maxFiles)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	for n := maxFiles - 1; n >= 1; n-- {
+		if err := os.Rename(numbered(n), numbered(n+1)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return os.Rename(path, numbered(1))
+}
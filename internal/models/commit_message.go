@@ -3,16 +3,18 @@ package models
 
 // CommitMessage represents the structured output from AI
 type CommitMessage struct {
-    Type      string   `json:"type"`
-    Scope     string   `json:"scope,omitempty"`
-    Subject   string   `json:"subject"`
-    Body      string   `json:"body,omitempty"`
-    Breaking  bool     `json:"breaking,omitempty"`
-    IssueRefs []string `json:"issue_refs,omitempty"`
-    CoAuthors []string `json:"co_authors,omitempty"`
+	Type           string   `json:"type" enum:"feat,fix,docs,style,refactor,perf,test,build,ci,chore" description:"Conventional commit type"`
+	Scope          string   `json:"scope,omitempty" description:"Optional area affected"`
+	Subject        string   `json:"subject" description:"Concise description in present tense"`
+	Body           string   `json:"body,omitempty" description:"Key changes"`
+	Breaking       bool     `json:"breaking,omitempty" description:"Whether the change breaks compatibility"`
+	BreakingChange string   `json:"breaking_change,omitempty" description:"When breaking: what breaks and how to migrate"`
+	IssueRefs      []string `json:"issue_refs,omitempty" description:"Issue references"`
+	CoAuthors      []string `json:"co_authors,omitempty" description:"Co-authors"`
 }
 
+// CommitOptions control the sync with the remote around a commit
 type CommitOptions struct {
-	NoSync bool
-	Force  bool
-}
\ No newline at end of file
+	NoSync bool // Commit only, without pulling and pushing
+	Force  bool // Allow force pushing a rewritten history
+}
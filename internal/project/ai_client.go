@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/baudevs/yolo.baudevs.com/internal/ai"
 	"github.com/baudevs/yolo.baudevs.com/internal/config"
 	"github.com/baudevs/yolo.baudevs.com/internal/types"
 	"github.com/sashabaranov/go-openai"
@@ -13,25 +14,34 @@ import (
 
 // AIClient represents an AI-powered client
 type AIClient struct {
-	client *openai.Client
+	client ai.Provider
 	model  string
 }
 
-// NewAIClient creates a new AI client
+// NewAIClient creates a new AI client asking the configured model
 func NewAIClient(cfg *config.Config) (*AIClient, error) {
-	client := openai.NewClient(cfg.OpenAI.APIKey)
+	model := cfg.AI.Model
+	if model == "" {
+		model = ai.DefaultModel
+	}
+
 	return &AIClient{
-		client: client,
-		model:  "gpt-4-turbo-preview",
+		client: ai.NewProvider(cfg.OpenAI.APIKey),
+		model:  model,
 	}, nil
 }
 
 // GenerateProjectName generates a project name based on the description
 func (c *AIClient) GenerateProjectName(description string) (string, string, error) {
+	system, err := ai.RenderPrompt("project.name.system", nil)
+	if err != nil {
+		return "", "", err
+	}
+
 	messages := []openai.ChatCompletionMessage{
 		{
 			Role:    openai.ChatMessageRoleSystem,
-			Content: "You are a project naming assistant. Generate a short, memorable project name based on the description.",
+			Content: system,
 		},
 		{
 			Role:    openai.ChatMessageRoleUser,
@@ -164,27 +174,24 @@ func (c *AIClient) GenerateProjectPlan(description string) (*types.Project, erro
 		},
 	}
 
-	messages := []openai.ChatCompletionMessage{
-		{
-			Role: openai.ChatMessageRoleSystem,
-			Content: `You are a project planning assistant that creates detailed project plans.
-Break down projects into:
-1. Epics (major features/components)
-2. Features (specific functionalities within epics)
-3. Tasks (individual units of work within features)
-
-Generate IDs following these patterns:
-- Epics: E001, E002, etc.
-- Features: F001, F002, etc.
-- Tasks: T001, T002, etc.
+	system, err := ai.RenderPrompt("project.plan.system", nil)
+	if err != nil {
+		return nil, err
+	}
 
-All items should start in "planned" status.
+	prompt, err := ai.RenderPrompt("project.plan", ai.PromptData{"Description": description})
+	if err != nil {
+		return nil, err
+	}
 
-IMPORTANT: Always generate a complete project structure with at least one epic, feature, and task.`,
+	messages := []openai.ChatCompletionMessage{
+		{
+			Role:    openai.ChatMessageRoleSystem,
+			Content: system,
 		},
 		{
 			Role:    openai.ChatMessageRoleUser,
-			Content: fmt.Sprintf("Create a project plan for this description:\n\n%s", description),
+			Content: prompt,
 		},
 	}
 
@@ -291,14 +298,24 @@ IMPORTANT: Always generate a complete project structure with at least one epic,
 
 // EnhanceDescription enhances a project description with more details
 func (c *AIClient) EnhanceDescription(description string) (string, error) {
+	system, err := ai.RenderPrompt("project.enhance.system", nil)
+	if err != nil {
+		return "", err
+	}
+
+	prompt, err := ai.RenderPrompt("project.enhance", ai.PromptData{"Description": description})
+	if err != nil {
+		return "", err
+	}
+
 	messages := []openai.ChatCompletionMessage{
 		{
 			Role:    openai.ChatMessageRoleSystem,
-			Content: "You are a project description enhancer. Add more details and clarity to the project description.",
+			Content: system,
 		},
 		{
 			Role:    openai.ChatMessageRoleUser,
-			Content: fmt.Sprintf("Enhance this project description with more details:\n\n%s", description),
+			Content: prompt,
 		},
 	}
 
@@ -327,10 +344,15 @@ func (c *AIClient) GenerateFileContent(fileType string, data interface{}) (strin
 		return "", fmt.Errorf("failed to marshal data: %w", err)
 	}
 
+	system, err := ai.RenderPrompt("project.file.system", ai.PromptData{"FileType": fileType})
+	if err != nil {
+		return "", err
+	}
+
 	messages := []openai.ChatCompletionMessage{
 		{
 			Role:    openai.ChatMessageRoleSystem,
-			Content: fmt.Sprintf("You are a file content generator for %s files. Generate detailed content based on the provided data.", fileType),
+			Content: system,
 		},
 		{
 			Role:    openai.ChatMessageRoleUser,
@@ -363,14 +385,24 @@ func (c *AIClient) ValidateProjectPlan(project *types.Project) error {
 		return fmt.Errorf("failed to marshal project: %w", err)
 	}
 
+	system, err := ai.RenderPrompt("project.validate.system", nil)
+	if err != nil {
+		return err
+	}
+
+	prompt, err := ai.RenderPrompt("project.validate", ai.PromptData{"Project": string(projectJSON)})
+	if err != nil {
+		return err
+	}
+
 	messages := []openai.ChatCompletionMessage{
 		{
 			Role:    openai.ChatMessageRoleSystem,
-			Content: "You are a project plan validator. Check if the project plan is complete and consistent.",
+			Content: system,
 		},
 		{
 			Role:    openai.ChatMessageRoleUser,
-			Content: fmt.Sprintf("Validate this project plan:\n\n%s", string(projectJSON)),
+			Content: prompt,
 		},
 	}
 
@@ -485,42 +517,24 @@ func (c *AIClient) GenerateCompleteProjectStructure(project *types.Project) (*Pr
 		return nil, fmt.Errorf("failed to marshal project: %w", err)
 	}
 
-	messages := []openai.ChatCompletionMessage{
-		{
-			Role: openai.ChatMessageRoleSystem,
-			Content: `You are a project structure generator. You will receive a project structure and generate detailed markdown content for each epic, feature, and task.
-Each markdown file should follow this structure:
-
-# [Item Title]
-
-## Overview
-[A clear and concise description of the item]
-
-## Status
-- Current Status: [planned/in-progress/completed]
-- Priority: [high/medium/low]
-- Timeline: [estimated duration]
-
-## Dependencies
-- [List of dependencies with IDs and brief explanations]
-
-## Acceptance Criteria
-- [List of specific, measurable criteria that must be met]
+	system, err := ai.RenderPrompt("project.structure.system", nil)
+	if err != nil {
+		return nil, err
+	}
 
-## Notes
-- [Any additional notes, considerations, or implementation details]
+	prompt, err := ai.RenderPrompt("project.structure", ai.PromptData{"Project": string(projectJSON)})
+	if err != nil {
+		return nil, err
+	}
 
-Make sure:
-1. Each markdown file follows the exact structure above
-2. Content is detailed and specific to the item
-3. All sections are properly formatted with correct Markdown syntax
-4. Dependencies and related items use correct IDs
-5. Status and priority levels are consistent
-6. Risk assessments are realistic and include mitigation strategies`,
+	messages := []openai.ChatCompletionMessage{
+		{
+			Role:    openai.ChatMessageRoleSystem,
+			Content: system,
 		},
 		{
 			Role:    openai.ChatMessageRoleUser,
-			Content: fmt.Sprintf("Generate project structure for: %s", string(projectJSON)),
+			Content: prompt,
 		},
 	}
 
@@ -555,20 +569,24 @@ Make sure:
 
 // GenerateCommitMessage generates a commit message based on the changes
 func (c *AIClient) GenerateCommitMessage(changes string) (string, error) {
+	system, err := ai.RenderPrompt("project.commit.system", nil)
+	if err != nil {
+		return "", err
+	}
+
+	prompt, err := ai.RenderPrompt("project.commit", ai.PromptData{"Changes": changes})
+	if err != nil {
+		return "", err
+	}
+
 	messages := []openai.ChatCompletionMessage{
 		{
-			Role: openai.ChatMessageRoleSystem,
-			Content: `You are a commit message generator following the Conventional Commits specification.
-Generate commit messages that are:
-1. Concise and clear
-2. Follow the format: <type>[optional scope]: <description>
-3. Types: feat, fix, docs, style, refactor, test, chore
-4. Use imperative mood ("add" not "added")
-5. No period at the end`,
+			Role:    openai.ChatMessageRoleSystem,
+			Content: system,
 		},
 		{
 			Role:    openai.ChatMessageRoleUser,
-			Content: fmt.Sprintf("Generate a commit message for these changes:\n\n%s", changes),
+			Content: prompt,
 		},
 	}
 
@@ -592,27 +610,20 @@ Generate commit messages that are:
 
 // GenerateEpicContent generates markdown content for an epic
 func (c *AIClient) GenerateEpicContent(epic types.Epic) (string, error) {
-	prompt := fmt.Sprintf(`Create a detailed markdown document for this epic:
-
-Name: %s
-Description: %s
-Status: %s
-
-The markdown should include:
-1. Title with ID and name
-2. Description section with comprehensive details
-3. Status section with current state
-4. Dependencies section if any
-5. List of features
-6. Any technical considerations
-7. Success criteria
+	system, err := ai.RenderPrompt("project.epic.system", nil)
+	if err != nil {
+		return "", err
+	}
 
-Format it as a well-structured markdown document.`, epic.Name, epic.Description, epic.Status)
+	prompt, err := ai.RenderPrompt("project.epic", ai.PromptData{"Epic": epic})
+	if err != nil {
+		return "", err
+	}
 
 	messages := []openai.ChatCompletionMessage{
 		{
 			Role:    openai.ChatMessageRoleSystem,
-			Content: "You are a technical documentation writer creating a detailed epic document.",
+			Content: system,
 		},
 		{
 			Role:    openai.ChatMessageRoleUser,
@@ -641,28 +652,23 @@ Format it as a well-structured markdown document.`, epic.Name, epic.Description,
 
 // GenerateFeatureContent generates markdown content for a feature
 func (c *AIClient) GenerateFeatureContent(feature types.Feature, parentEpic types.Epic) (string, error) {
-	prompt := fmt.Sprintf(`Create a detailed markdown document for this feature:
-
-Name: %s
-Description: %s
-Status: %s
-Parent Epic: [%s] %s
-
-The markdown should include:
-1. Title with ID and name
-2. Description section with implementation details
-3. Status section with current state
-4. Parent epic reference
-5. List of tasks
-6. Technical requirements
-7. Success criteria
+	system, err := ai.RenderPrompt("project.feature.system", nil)
+	if err != nil {
+		return "", err
+	}
 
-Format it as a well-structured markdown document.`, feature.Name, feature.Description, feature.Status, parentEpic.ID, parentEpic.Name)
+	prompt, err := ai.RenderPrompt("project.feature", ai.PromptData{
+		"Feature": feature,
+		"Epic":    parentEpic,
+	})
+	if err != nil {
+		return "", err
+	}
 
 	messages := []openai.ChatCompletionMessage{
 		{
 			Role:    openai.ChatMessageRoleSystem,
-			Content: "You are a technical documentation writer creating a detailed feature document.",
+			Content: system,
 		},
 		{
 			Role:    openai.ChatMessageRoleUser,
@@ -691,29 +697,24 @@ Format it as a well-structured markdown document.`, feature.Name, feature.Descri
 
 // GenerateTaskContent generates markdown content for a task
 func (c *AIClient) GenerateTaskContent(task types.Task, parentFeature types.Feature, parentEpic types.Epic) (string, error) {
-	prompt := fmt.Sprintf(`Create a detailed markdown document for this task:
-
-Name: %s
-Description: %s
-Status: %s
-Parent Feature: [%s] %s
-Parent Epic: [%s] %s
-
-The markdown should include:
-1. Title with ID and name
-2. Description section with specific implementation details
-3. Status section with current state
-4. Parent feature and epic references
-5. Technical requirements
-6. Success criteria
-7. Implementation steps
+	system, err := ai.RenderPrompt("project.task.system", nil)
+	if err != nil {
+		return "", err
+	}
 
-Format it as a well-structured markdown document.`, task.Name, task.Description, task.Status, parentFeature.ID, parentFeature.Name, parentEpic.ID, parentEpic.Name)
+	prompt, err := ai.RenderPrompt("project.task", ai.PromptData{
+		"Task":    task,
+		"Feature": parentFeature,
+		"Epic":    parentEpic,
+	})
+	if err != nil {
+		return "", err
+	}
 
 	messages := []openai.ChatCompletionMessage{
 		{
 			Role:    openai.ChatMessageRoleSystem,
-			Content: "You are a technical documentation writer creating a detailed task document.",
+			Content: system,
 		},
 		{
 			Role:    openai.ChatMessageRoleUser,
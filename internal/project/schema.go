@@ -1,30 +1,19 @@
 package project
 
+import "github.com/baudevs/yolo.baudevs.com/internal/ai"
+
 // JSONSchemaType represents a JSON Schema type
-type JSONSchemaType string
+type JSONSchemaType = ai.JSONSchemaType
 
 const (
-	JSONSchemaTypeString  JSONSchemaType = "string"
-	JSONSchemaTypeNumber  JSONSchemaType = "number"
-	JSONSchemaTypeInteger JSONSchemaType = "integer"
-	JSONSchemaTypeObject  JSONSchemaType = "object"
-	JSONSchemaTypeArray   JSONSchemaType = "array"
-	JSONSchemaTypeBoolean JSONSchemaType = "boolean"
-	JSONSchemaTypeNull    JSONSchemaType = "null"
+	JSONSchemaTypeString  = ai.JSONSchemaTypeString
+	JSONSchemaTypeNumber  = ai.JSONSchemaTypeNumber
+	JSONSchemaTypeInteger = ai.JSONSchemaTypeInteger
+	JSONSchemaTypeObject  = ai.JSONSchemaTypeObject
+	JSONSchemaTypeArray   = ai.JSONSchemaTypeArray
+	JSONSchemaTypeBoolean = ai.JSONSchemaTypeBoolean
+	JSONSchemaTypeNull    = ai.JSONSchemaTypeNull
 )
 
 // JSONSchemaDefinition represents a JSON Schema definition
-type JSONSchemaDefinition struct {
-	Type        JSONSchemaType                  `json:"type,omitempty"`
-	Description string                          `json:"description,omitempty"`
-	Properties  map[string]JSONSchemaDefinition `json:"properties,omitempty"`
-	Items       *JSONSchemaDefinition           `json:"items,omitempty"`
-	Required    []string                        `json:"required,omitempty"`
-	Enum        []string                        `json:"enum,omitempty"`
-	Ref         string                          `json:"$ref,omitempty"`
-	OneOf       []JSONSchemaDefinition          `json:"oneOf,omitempty"`
-	AnyOf       []JSONSchemaDefinition          `json:"anyOf,omitempty"`
-	AllOf       []JSONSchemaDefinition          `json:"allOf,omitempty"`
-	Not         *JSONSchemaDefinition           `json:"not,omitempty"`
-	Definitions map[string]JSONSchemaDefinition `json:"definitions,omitempty"`
-}
+type JSONSchemaDefinition = ai.JSONSchemaDefinition
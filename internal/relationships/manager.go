@@ -7,8 +7,11 @@ import (
 	"path/filepath"
 	"regexp"
 	"strings"
+	"time"
 
 	"github.com/baudevs/yolo.baudevs.com/internal/ai"
+	"github.com/baudevs/yolo.baudevs.com/internal/logging"
+	"github.com/baudevs/yolo.baudevs.com/internal/utils"
 )
 
 type WorkItemType string
@@ -39,7 +42,8 @@ func NewManager(aiClient *ai.Client) *RelationshipManager {
 	}
 }
 
-// FindOrCreateParent finds or suggests creating a parent work item
+// FindOrCreateParent finds or suggests creating a parent work item.
+// Candidates are shortlisted locally by similarity, the AI only makes the final choice.
 func (m *RelationshipManager) FindOrCreateParent(ctx context.Context, itemType WorkItemType, description string, existingItems []WorkItem) (*WorkItem, bool, error) {
 	var parentType WorkItemType
 	switch itemType {
@@ -51,43 +55,46 @@ func (m *RelationshipManager) FindOrCreateParent(ctx context.Context, itemType W
 		return nil, false, nil // Epics don't have parents
 	}
 
-	prompt := fmt.Sprintf(`Given this %s description:
-"%s"
-
-Analyze these existing %ss and determine if this %s should:
-1. Be linked to an existing %s (respond with ID)
-2. Need a new %s to be created (respond with "NEW")
-
-Existing %ss:
-%s
-
-Consider:
-- Scope and goals alignment
-- Natural relationships
-- Project structure
+	var candidates []WorkItem
+	for _, match := range NewSimilarityIndex(existingItems).Similar(description, parentType, maxParentCandidates) {
+		if match.Score >= minParentScore {
+			candidates = append(candidates, match.Item)
+		}
+	}
+	if len(candidates) == 0 {
+		return nil, true, nil // Nothing related enough to ask about
+	}
 
-Respond with just the %s ID (e.g., "E001") or "NEW" if a new %s should be created.`,
-		itemType, description, parentType, itemType, parentType, parentType, parentType,
-		formatItemsForAI(filterByType(existingItems, parentType)), parentType, parentType)
+	prompt, err := ai.RenderPrompt("relationships.parent", ai.PromptData{
+		"ItemType":    itemType,
+		"Description": description,
+		"ParentType":  parentType,
+		"Candidates":  formatItemsForAI(candidates),
+	})
+	if err != nil {
+		return nil, false, err
+	}
 
 	response, err := m.aiClient.Ask(ctx, prompt)
 	if err != nil {
 		return nil, false, err
 	}
 
-	response = strings.TrimSpace(response)
+	response = strings.Trim(strings.TrimSpace(response), `"[].`)
 	if response == "NEW" {
 		return nil, true, nil
 	}
 
-	// Find existing parent
-	for _, item := range existingItems {
-		if item.Type == parentType && item.ID == response {
+	for _, item := range candidates {
+		if item.ID == response {
 			return &item, false, nil
 		}
 	}
 
-	return nil, false, nil
+	// The AI answered outside the shortlist, don't guess which item it meant
+	logging.Warn("AI chose a parent outside the candidates, creating a new one",
+		"answer", response, "type", parentType)
+	return nil, true, nil
 }
 
 // SuggestChildren suggests child items that should be created
@@ -102,16 +109,14 @@ func (m *RelationshipManager) SuggestChildren(ctx context.Context, itemType Work
 		return nil, nil // Tasks don't have children
 	}
 
-	prompt := fmt.Sprintf(`Given this %s description:
-"%s"
-
-Suggest 3-5 %ss that would be needed to implement this %s. Each %s should be:
-- Specific and actionable
-- Clear in scope
-- Contribute directly to the %s's goals
-
-Respond with one %s title per line, no numbers or bullets.`,
-		itemType, description, childType, itemType, childType, itemType, childType)
+	prompt, err := ai.RenderPrompt("relationships.children", ai.PromptData{
+		"ItemType":    itemType,
+		"Description": description,
+		"ChildType":   childType,
+	})
+	if err != nil {
+		return nil, err
+	}
 
 	response, err := m.aiClient.Ask(ctx, prompt)
 	if err != nil {
@@ -189,19 +194,7 @@ func (m *RelationshipManager) LoadWorkItems(types ...WorkItemType) ([]WorkItem,
 	var items []WorkItem
 
 	for _, itemType := range types {
-		var dir string
-		var prefix string
-		switch itemType {
-		case Epic:
-			dir = "epics"
-			prefix = "E"
-		case Feature:
-			dir = "features"
-			prefix = "F"
-		case Task:
-			dir = "tasks"
-			prefix = "T"
-		}
+		dir, prefix := itemLocation(itemType)
 
 		files, err := filepath.Glob(filepath.Join("yolo", dir, "*.md"))
 		if err != nil {
@@ -247,6 +240,294 @@ func (m *RelationshipManager) LoadWorkItems(types ...WorkItemType) ([]WorkItem,
 	return items, nil
 }
 
+// CreateItem writes a new work item file, linked to its parent when one is given
+func (m *RelationshipManager) CreateItem(itemType WorkItemType, title, description string, parent *WorkItem) (*WorkItem, error) {
+	dir, prefix := itemLocation(itemType)
+	if dir == "" {
+		return nil, fmt.Errorf("unknown work item type: %s", itemType)
+	}
+
+	id := utils.GenerateID(prefix)
+	path := filepath.Join("yolo", dir, fmt.Sprintf("%s.md", id))
+	today := time.Now().Format("2006-01-02")
+
+	content := fmt.Sprintf(`# [%s] %s
+
+## Status: planning
+Created: %s
+Last Updated: %s
+
+## Description
+%s
+
+## Success Criteria
+- [ ] %s implemented
+- [ ] Tests added
+- [ ] Documentation updated
+- [ ] Code reviewed
+
+## Relationships
+<!-- YOLO-LINKS-START -->
+<!-- YOLO-LINKS-END -->
+`, id, title, today, today, strings.TrimSpace(description), itemType)
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create %s directory: %w", dir, err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		return nil, fmt.Errorf("failed to write %s file: %w", strings.ToLower(string(itemType)), err)
+	}
+
+	item := &WorkItem{
+		Type:        itemType,
+		ID:          id,
+		Title:       title,
+		Description: description,
+		Status:      "planning",
+		Path:        path,
+		Content:     content,
+	}
+
+	if parent != nil {
+		if err := m.Link(*item, *parent); err != nil {
+			return item, err
+		}
+	}
+
+	return item, nil
+}
+
+// Link records a parent-child relationship in both work item files
+func (m *RelationshipManager) Link(child, parent WorkItem) error {
+	if itemLevel(parent.Type) >= itemLevel(child.Type) {
+		return fmt.Errorf("%s %s cannot be the parent of %s %s", parent.Type, parent.ID, child.Type, child.ID)
+	}
+
+	if err := addLink(child.Path, fmt.Sprintf("- Parent %s: [%s] %s", parent.Type, parent.ID, parent.Title)); err != nil {
+		return fmt.Errorf("failed to link %s: %w", child.ID, err)
+	}
+	if err := addLink(parent.Path, fmt.Sprintf("- %s: [%s] %s", child.Type, child.ID, child.Title)); err != nil {
+		return fmt.Errorf("failed to link %s: %w", parent.ID, err)
+	}
+	return nil
+}
+
+// SetStatus changes the status of a work item and bumps its last updated date
+func (m *RelationshipManager) SetStatus(item WorkItem, status string) error {
+	content, err := os.ReadFile(item.Path)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", item.ID, err)
+	}
+
+	statusLine := regexp.MustCompile(`(?m)^## Status: .*$`)
+	if !statusLine.Match(content) {
+		return fmt.Errorf("%s has no status line", item.ID)
+	}
+	content = statusLine.ReplaceAllLiteral(content, []byte("## Status: "+status))
+
+	updatedLine := regexp.MustCompile(`(?m)^Last Updated: .*$`)
+	content = updatedLine.ReplaceAllLiteral(content, []byte("Last Updated: "+time.Now().Format("2006-01-02")))
+
+	return os.WriteFile(item.Path, content, 0644)
+}
+
+// HasParent reports whether the item is linked to the given parent
+func (item WorkItem) HasParent(id string) bool {
+	re := regexp.MustCompile(`(?m)^(?:- Parent )?(?:Epic|Feature): \[` + regexp.QuoteMeta(id) + `\]`)
+	return re.MatchString(item.Content)
+}
+
+// SetSection replaces the body of a "## heading" section of a work item,
+// adding the section at the end when it does not exist yet
+func (m *RelationshipManager) SetSection(item WorkItem, heading, body string) error {
+	content, err := os.ReadFile(item.Path)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", item.ID, err)
+	}
+
+	section := "## " + heading + "\n" + strings.TrimSpace(body) + "\n"
+	contentStr := string(content)
+
+	// The section runs until the next second-level heading or the end of the file
+	existing := regexp.MustCompile(`(?ms)^## ` + regexp.QuoteMeta(heading) + `[ \t]*\n.*?(?:^## |\z)`)
+	if loc := existing.FindStringIndex(contentStr); loc != nil {
+		end := loc[1]
+		if strings.HasSuffix(contentStr[loc[0]:end], "## ") {
+			end -= len("## ")
+			section += "\n"
+		}
+		contentStr = contentStr[:loc[0]] + section + contentStr[end:]
+	} else {
+		contentStr = strings.TrimRight(contentStr, "\n") + "\n\n" + section
+	}
+
+	updatedLine := regexp.MustCompile(`(?m)^Last Updated: .*$`)
+	contentStr = updatedLine.ReplaceAllLiteralString(contentStr, "Last Updated: "+time.Now().Format("2006-01-02"))
+
+	return os.WriteFile(item.Path, []byte(contentStr), 0644)
+}
+
+// ActivitySection is the work item section recording the commits made for it
+const ActivitySection = "Activity"
+
+// AddActivity appends a dated entry to the activity section of a work item
+func (m *RelationshipManager) AddActivity(item WorkItem, entry string) error {
+	content, err := os.ReadFile(item.Path)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", item.ID, err)
+	}
+
+	var body string
+	existing := regexp.MustCompile(`(?ms)^## ` + ActivitySection + `[ \t]*\n(.*?)(?:^## |\z)`)
+	if match := existing.FindStringSubmatch(string(content)); match != nil {
+		body = strings.TrimSpace(match[1]) + "\n"
+	}
+	body += fmt.Sprintf("- %s %s", time.Now().Format("2006-01-02"), entry)
+
+	return m.SetSection(item, ActivitySection, body)
+}
+
+// ReplaceActivity replaces the activity entries starting with one of the
+// prefixes (after their date) by a single dated entry, in place of the first
+// of them. The entry is appended when none matches.
+func (m *RelationshipManager) ReplaceActivity(item WorkItem, prefixes []string, entry string) error {
+	content, err := os.ReadFile(item.Path)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", item.ID, err)
+	}
+
+	existing := regexp.MustCompile(`(?ms)^## ` + ActivitySection + `[ \t]*\n(.*?)(?:^## |\z)`)
+	match := existing.FindStringSubmatch(string(content))
+	if match == nil || len(prefixes) == 0 {
+		return m.AddActivity(item, entry)
+	}
+
+	dated := regexp.MustCompile(`^- \d{4}-\d{2}-\d{2} `)
+	var lines []string
+	replaced := false
+	for _, line := range strings.Split(strings.TrimSpace(match[1]), "\n") {
+		text := dated.ReplaceAllString(line, "")
+		if text != line && hasAnyPrefix(text, prefixes) {
+			if !replaced {
+				lines = append(lines, fmt.Sprintf("- %s %s", time.Now().Format("2006-01-02"), entry))
+				replaced = true
+			}
+			continue
+		}
+		lines = append(lines, line)
+	}
+	if !replaced {
+		return m.AddActivity(item, entry)
+	}
+
+	return m.SetSection(item, ActivitySection, strings.Join(lines, "\n"))
+}
+
+// hasAnyPrefix reports whether text starts with one of the prefixes
+func hasAnyPrefix(text string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(text, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// HasSection reports whether the item has a "## heading" section
+func (item WorkItem) HasSection(heading string) bool {
+	re := regexp.MustCompile(`(?m)^## ` + regexp.QuoteMeta(heading) + `[ \t]*$`)
+	return re.MatchString(item.Content)
+}
+
+// Ancestors returns the parents of an item, then their parents, nearest first
+func Ancestors(item WorkItem, items []WorkItem) []WorkItem {
+	var ancestors []WorkItem
+	seen := map[string]bool{item.ID: true}
+	queue := []WorkItem{item}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, candidate := range items {
+			if seen[candidate.ID] || itemLevel(candidate.Type) >= itemLevel(current.Type) {
+				continue
+			}
+			if current.HasParent(candidate.ID) {
+				seen[candidate.ID] = true
+				ancestors = append(ancestors, candidate)
+				queue = append(queue, candidate)
+			}
+		}
+	}
+	return ancestors
+}
+
+// TranslationPath returns where the translation of an item into lang is kept.
+// Translations live in a subdirectory so they are not loaded as work items.
+func TranslationPath(item WorkItem, lang string) string {
+	return filepath.Join(filepath.Dir(item.Path), "translations", fmt.Sprintf("%s.%s.md", item.ID, strings.ToLower(lang)))
+}
+
+// LinkTranslation records a translation in the links section of the original item
+func (m *RelationshipManager) LinkTranslation(item WorkItem, lang, path string) error {
+	rel, err := filepath.Rel(filepath.Dir(item.Path), path)
+	if err != nil {
+		rel = path
+	}
+	if err := addLink(item.Path, fmt.Sprintf("- Translation (%s): %s", lang, filepath.ToSlash(rel))); err != nil {
+		return fmt.Errorf("failed to link translation of %s: %w", item.ID, err)
+	}
+	return nil
+}
+
+// addLink adds a line to the links section of a work item, once
+func addLink(path, line string) error {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	contentStr := string(content)
+	for _, existing := range strings.Split(contentStr, "\n") {
+		if strings.TrimSpace(existing) == line {
+			return nil
+		}
+	}
+
+	const endMarker = "<!-- YOLO-LINKS-END -->"
+	if !strings.Contains(contentStr, endMarker) {
+		contentStr = strings.TrimSpace(contentStr) + "\n\n## Relationships\n<!-- YOLO-LINKS-START -->\n" + endMarker + "\n"
+	}
+	contentStr = strings.Replace(contentStr, endMarker, line+"\n"+endMarker, 1)
+
+	return os.WriteFile(path, []byte(contentStr), 0644)
+}
+
+// itemLocation returns the directory and ID prefix of a work item type
+func itemLocation(itemType WorkItemType) (dir, prefix string) {
+	switch itemType {
+	case Epic:
+		return "epics", "E"
+	case Feature:
+		return "features", "F"
+	case Task:
+		return "tasks", "T"
+	}
+	return "", ""
+}
+
+// itemLevel orders work item types from the broadest to the most specific
+func itemLevel(itemType WorkItemType) int {
+	switch itemType {
+	case Epic:
+		return 1
+	case Feature:
+		return 2
+	case Task:
+		return 3
+	}
+	return 0
+}
+
 func filterByType(items []WorkItem, itemType WorkItemType) []WorkItem {
 	var filtered []WorkItem
 	for _, item := range items {
@@ -0,0 +1,132 @@
+package relationships
+
+import (
+	"math"
+	"sort"
+
+	"github.com/baudevs/yolo.baudevs.com/internal/retrieval"
+)
+
+const (
+	// DuplicateThreshold is the similarity above which a new item is reported as a likely duplicate
+	DuplicateThreshold = 0.5
+	// minParentScore is the similarity a work item needs to be offered as a parent
+	minParentScore = 0.05
+	// maxParentCandidates bounds how many parents the AI chooses from
+	maxParentCandidates = 5
+)
+
+// Match is a work item scored against a piece of text
+type Match struct {
+	Item  WorkItem
+	Score float64 // Cosine similarity, from 0 to 1
+}
+
+// SimilarityIndex scores work items by TF-IDF similarity of their titles and descriptions
+type SimilarityIndex struct {
+	items   []WorkItem
+	titles  []map[string]float64
+	vectors []map[string]float64
+	docFreq map[string]int
+}
+
+// NewSimilarityIndex indexes the given work items
+func NewSimilarityIndex(items []WorkItem) *SimilarityIndex {
+	idx := &SimilarityIndex{
+		items:   items,
+		docFreq: make(map[string]int),
+	}
+
+	terms := make([]map[string]int, len(items))
+	for i, item := range items {
+		terms[i] = termCounts(item.Title, item.Description)
+		for term := range terms[i] {
+			idx.docFreq[term]++
+		}
+	}
+	for i, counts := range terms {
+		idx.titles = append(idx.titles, idx.weigh(termCounts(items[i].Title, "")))
+		idx.vectors = append(idx.vectors, idx.weigh(counts))
+	}
+
+	return idx
+}
+
+// Similar returns the items of the given type most similar to text, best first.
+// Text is compared to both the title alone and the whole item, so a short
+// description is not diluted by a long generated body.
+func (idx *SimilarityIndex) Similar(text string, itemType WorkItemType, limit int) []Match {
+	query := idx.weigh(termCounts("", text))
+
+	var matches []Match
+	for i, item := range idx.items {
+		if item.Type != itemType {
+			continue
+		}
+		score := math.Max(cosine(query, idx.titles[i]), cosine(query, idx.vectors[i]))
+		if score > 0 {
+			matches = append(matches, Match{Item: item, Score: score})
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].Score != matches[j].Score {
+			return matches[i].Score > matches[j].Score
+		}
+		return matches[i].Item.ID < matches[j].Item.ID
+	})
+	if limit > 0 && len(matches) > limit {
+		matches = matches[:limit]
+	}
+	return matches
+}
+
+// FindDuplicates returns the existing items of the same type that look like the new one
+func FindDuplicates(itemType WorkItemType, text string, items []WorkItem) []Match {
+	var duplicates []Match
+	for _, match := range NewSimilarityIndex(items).Similar(text, itemType, 0) {
+		if match.Score >= DuplicateThreshold {
+			duplicates = append(duplicates, match)
+		}
+	}
+	return duplicates
+}
+
+// weigh turns term counts into a TF-IDF vector.
+// The smoothed IDF keeps terms unknown to the index from dominating.
+func (idx *SimilarityIndex) weigh(counts map[string]int) map[string]float64 {
+	n := float64(len(idx.items))
+	vector := make(map[string]float64, len(counts))
+	for term, count := range counts {
+		idf := math.Log((1+n)/(1+float64(idx.docFreq[term]))) + 1
+		vector[term] = (1 + math.Log(float64(count))) * idf
+	}
+	return vector
+}
+
+// termCounts counts the terms of a work item, the title counting twice
+func termCounts(title, description string) map[string]int {
+	counts := make(map[string]int)
+	for _, token := range retrieval.Tokenize(title) {
+		counts[token] += 2
+	}
+	for _, token := range retrieval.Tokenize(description) {
+		counts[token]++
+	}
+	return counts
+}
+
+func cosine(a, b map[string]float64) float64 {
+	var dot, normA, normB float64
+	for term, weight := range a {
+		dot += weight * b[term]
+		normA += weight * weight
+	}
+	for _, weight := range b {
+		normB += weight * weight
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
@@ -0,0 +1,83 @@
+package retrieval
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// EmbedFunc returns one embedding vector per text, in order
+type EmbedFunc func(ctx context.Context, texts []string) ([][]float32, error)
+
+// EstimateTokens approximates the token count of text at four characters per token
+func EstimateTokens(text string) int {
+	return (len(text) + 3) / 4
+}
+
+// Rerank reorders results by embedding similarity to the query,
+// blended with their keyword rank so exact matches are not lost
+func Rerank(ctx context.Context, embed EmbedFunc, query string, results []Result) ([]Result, error) {
+	if len(results) == 0 {
+		return results, nil
+	}
+
+	texts := make([]string, 0, len(results)+1)
+	texts = append(texts, query)
+	for _, r := range results {
+		texts = append(texts, r.Title+"\n"+r.Text)
+	}
+
+	vectors, err := embed(ctx, texts)
+	if err != nil {
+		return nil, err
+	}
+
+	reranked := make([]Result, len(results))
+	for i, r := range results {
+		keyword := 1 - float64(i)/float64(len(results))
+		r.Score = 0.5*keyword + 0.5*cosine(vectors[0], vectors[i+1])
+		reranked[i] = r
+	}
+	sortResults(reranked)
+
+	return reranked, nil
+}
+
+// Pack renders the best results that fit in the token budget as prompt context.
+// Results that do not fit are skipped, so smaller ones further down can still be used.
+// It returns the context and the results it contains.
+func Pack(results []Result, budget int) (string, []Result) {
+	var sb strings.Builder
+	var packed []Result
+	used := 0
+
+	for _, r := range results {
+		section := fmt.Sprintf("--- %s (%s) ---\n%s\n\n", r.Citation(), r.Kind, strings.TrimSpace(r.Text))
+		tokens := EstimateTokens(section)
+		if used+tokens > budget {
+			continue
+		}
+		sb.WriteString(section)
+		packed = append(packed, r)
+		used += tokens
+	}
+
+	return strings.TrimSpace(sb.String()), packed
+}
+
+func cosine(a, b []float32) float64 {
+	var dot, normA, normB float64
+	for i := range a {
+		if i >= len(b) {
+			break
+		}
+		dot += float64(a[i]) * float64(b[i])
+		normA += float64(a[i]) * float64(a[i])
+		normB += float64(b[i]) * float64(b[i])
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
@@ -0,0 +1,227 @@
+package retrieval
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Document kinds, used to label context sent to the AI
+const (
+	KindEpic     = "epic"
+	KindFeature  = "feature"
+	KindTask     = "task"
+	KindStrategy = "strategy"
+	KindReadme   = "readme"
+	KindSource   = "source"
+)
+
+const (
+	// chunkLines is the size of the windows source files are split into
+	chunkLines = 60
+	// maxSourceSize skips generated and vendored blobs that would drown the index
+	maxSourceSize = 256 * 1024
+)
+
+// Document is a searchable piece of the project
+type Document struct {
+	Path      string // Path relative to the project root
+	Kind      string
+	Title     string
+	Text      string
+	StartLine int // First line of a source chunk, 0 for whole files
+	EndLine   int
+}
+
+// Citation returns the reference the AI is asked to cite, e.g. internal/ai/client.go:1-60
+func (d Document) Citation() string {
+	if d.StartLine == 0 {
+		return d.Path
+	}
+	return fmt.Sprintf("%s:%d-%d", d.Path, d.StartLine, d.EndLine)
+}
+
+// sourceExtensions lists the files indexed as source code
+var sourceExtensions = map[string]bool{
+	".go": true, ".js": true, ".jsx": true, ".ts": true, ".tsx": true,
+	".py": true, ".rb": true, ".rs": true, ".java": true, ".kt": true,
+	".swift": true, ".c": true, ".h": true, ".cpp": true, ".cs": true,
+	".php": true, ".sh": true, ".sql": true, ".proto": true,
+	".yml": true, ".yaml": true, ".toml": true, ".md": true,
+}
+
+// skippedDirs are never walked when the project is not a git repository
+var skippedDirs = map[string]bool{
+	".git": true, "node_modules": true, "vendor": true, "dist": true,
+	"build": true, ".yolo": true, ".yolo-debug": true,
+}
+
+var workItemTitle = regexp.MustCompile(`(?m)^# (\[[A-Z]\d+\] .+)$`)
+
+// Collect gathers the work items, strategy, readme and source files under root
+func Collect(root string) ([]Document, error) {
+	var docs []Document
+
+	workItems := map[string]string{
+		"epics":    KindEpic,
+		"features": KindFeature,
+		"tasks":    KindTask,
+	}
+	for _, dir := range []string{"epics", "features", "tasks"} {
+		files, err := filepath.Glob(filepath.Join(root, "yolo", dir, "*.md"))
+		if err != nil {
+			return nil, fmt.Errorf("failed to list %s: %w", dir, err)
+		}
+		sort.Strings(files)
+		for _, file := range files {
+			if doc, ok := readDocument(root, file, workItems[dir]); ok {
+				docs = append(docs, doc)
+			}
+		}
+	}
+
+	for name, kind := range map[string]string{"STRATEGY.md": KindStrategy, "README.md": KindReadme} {
+		if doc, ok := readDocument(root, filepath.Join(root, name), kind); ok {
+			docs = append(docs, doc)
+		}
+	}
+
+	files, err := sourceFiles(root)
+	if err != nil {
+		return nil, err
+	}
+	for _, file := range files {
+		rel := filepath.ToSlash(file)
+		if rel == "README.md" || rel == "STRATEGY.md" || strings.HasPrefix(rel, "yolo/") {
+			continue // Already indexed as whole documents
+		}
+		docs = append(docs, readSourceChunks(root, file)...)
+	}
+
+	sort.SliceStable(docs, func(i, j int) bool {
+		if docs[i].Path != docs[j].Path {
+			return docs[i].Path < docs[j].Path
+		}
+		return docs[i].StartLine < docs[j].StartLine
+	})
+
+	return docs, nil
+}
+
+func readDocument(root, path, kind string) (Document, bool) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return Document{}, false
+	}
+
+	rel, err := filepath.Rel(root, path)
+	if err != nil {
+		rel = path
+	}
+
+	title := filepath.Base(path)
+	if match := workItemTitle.FindStringSubmatch(string(content)); match != nil {
+		title = match[1]
+	}
+
+	return Document{
+		Path:  filepath.ToSlash(rel),
+		Kind:  kind,
+		Title: title,
+		Text:  string(content),
+	}, true
+}
+
+// readSourceChunks splits a source file into fixed windows of lines
+func readSourceChunks(root, rel string) []Document {
+	path := filepath.Join(root, rel)
+	info, err := os.Stat(path)
+	if err != nil || info.Size() > maxSourceSize {
+		return nil
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil || bytes.IndexByte(content, 0) != -1 {
+		return nil // Unreadable or binary
+	}
+
+	var docs []Document
+	var lines []string
+	start := 1
+	flush := func(end int) {
+		if len(lines) == 0 {
+			return
+		}
+		docs = append(docs, Document{
+			Path:      filepath.ToSlash(rel),
+			Kind:      KindSource,
+			Title:     filepath.Base(rel),
+			Text:      strings.Join(lines, "\n"),
+			StartLine: start,
+			EndLine:   end,
+		})
+		lines = nil
+		start = end + 1
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	scanner.Buffer(make([]byte, 0, 64*1024), maxSourceSize)
+	n := 0
+	for scanner.Scan() {
+		n++
+		lines = append(lines, scanner.Text())
+		if len(lines) == chunkLines {
+			flush(n)
+		}
+	}
+	flush(n)
+
+	return docs
+}
+
+// sourceFiles lists indexable files relative to root, preferring git's view
+// so ignored files stay out of the index
+func sourceFiles(root string) ([]string, error) {
+	var files []string
+
+	cmd := exec.Command("git", "ls-files", "--cached", "--others", "--exclude-standard")
+	cmd.Dir = root
+	if output, err := cmd.Output(); err == nil {
+		for _, file := range strings.Split(string(output), "\n") {
+			if file != "" && sourceExtensions[filepath.Ext(file)] {
+				files = append(files, filepath.FromSlash(file))
+			}
+		}
+		return files, nil
+	}
+
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return nil
+		}
+		if info.IsDir() {
+			if path != root && (skippedDirs[info.Name()] || strings.HasPrefix(info.Name(), ".")) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if sourceExtensions[filepath.Ext(path)] {
+			rel, err := filepath.Rel(root, path)
+			if err == nil {
+				files = append(files, rel)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to walk project: %w", err)
+	}
+
+	return files, nil
+}
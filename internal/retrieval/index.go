@@ -0,0 +1,172 @@
+package retrieval
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// BM25 parameters, the usual defaults
+const (
+	bm25K1 = 1.2
+	bm25B  = 0.75
+)
+
+// Result is a document matched by a search
+type Result struct {
+	Document
+	Score float64
+}
+
+// Index is an in-memory BM25 index over documents
+type Index struct {
+	docs      []Document
+	terms     []map[string]int // Term frequencies per document
+	lengths   []int
+	avgLength float64
+	docFreq   map[string]int
+}
+
+// NewIndex indexes the given documents
+func NewIndex(docs []Document) *Index {
+	idx := &Index{
+		docs:    docs,
+		terms:   make([]map[string]int, len(docs)),
+		lengths: make([]int, len(docs)),
+		docFreq: make(map[string]int),
+	}
+
+	total := 0
+	for i, doc := range docs {
+		// The path and title are indexed too, so file and item names match
+		tokens := Tokenize(doc.Path + " " + doc.Title + " " + doc.Text)
+		freq := make(map[string]int, len(tokens))
+		for _, token := range tokens {
+			freq[token]++
+		}
+		for term := range freq {
+			idx.docFreq[term]++
+		}
+		idx.terms[i] = freq
+		idx.lengths[i] = len(tokens)
+		total += len(tokens)
+	}
+	if len(docs) > 0 {
+		idx.avgLength = float64(total) / float64(len(docs))
+	}
+
+	return idx
+}
+
+// Len returns the number of indexed documents
+func (idx *Index) Len() int {
+	return len(idx.docs)
+}
+
+// Search returns up to limit documents matching the query, best first
+func (idx *Index) Search(query string, limit int) []Result {
+	queryTerms := uniqueTerms(Tokenize(query))
+	if len(queryTerms) == 0 || len(idx.docs) == 0 {
+		return nil
+	}
+
+	n := float64(len(idx.docs))
+	var results []Result
+	for i, freq := range idx.terms {
+		score := 0.0
+		for _, term := range queryTerms {
+			tf := float64(freq[term])
+			if tf == 0 {
+				continue
+			}
+			df := float64(idx.docFreq[term])
+			idf := math.Log(1 + (n-df+0.5)/(df+0.5))
+			norm := bm25K1 * (1 - bm25B + bm25B*float64(idx.lengths[i])/idx.avgLength)
+			score += idf * tf * (bm25K1 + 1) / (tf + norm)
+		}
+		if score > 0 {
+			results = append(results, Result{Document: idx.docs[i], Score: score})
+		}
+	}
+
+	sortResults(results)
+	if limit > 0 && len(results) > limit {
+		results = results[:limit]
+	}
+	return results
+}
+
+// sortResults orders by score, then by citation so ties are deterministic
+func sortResults(results []Result) {
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		return results[i].Citation() < results[j].Citation()
+	})
+}
+
+// stopWords are too common to tell documents apart
+var stopWords = map[string]bool{
+	"a": true, "an": true, "and": true, "are": true, "as": true, "at": true,
+	"be": true, "by": true, "can": true, "do": true, "does": true, "for": true,
+	"from": true, "how": true, "if": true, "in": true, "is": true, "it": true,
+	"of": true, "on": true, "or": true, "that": true, "the": true, "this": true,
+	"to": true, "was": true, "we": true, "what": true, "when": true, "where": true,
+	"which": true, "who": true, "why": true, "with": true, "you": true,
+}
+
+// Tokenize lowercases text and splits it into terms,
+// breaking identifiers such as parseConfig or load_work_items into words
+func Tokenize(text string) []string {
+	var tokens []string
+	add := func(word string) {
+		word = strings.ToLower(word)
+		if len(word) > 1 && !stopWords[word] {
+			tokens = append(tokens, word)
+		}
+	}
+
+	words := strings.FieldsFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for _, word := range words {
+		parts := splitCamelCase(word)
+		if len(parts) > 1 {
+			add(word) // Keep the whole identifier for exact matches
+		}
+		for _, part := range parts {
+			add(part)
+		}
+	}
+
+	return tokens
+}
+
+func splitCamelCase(word string) []string {
+	runes := []rune(word)
+	var parts []string
+	start := 0
+	for i := 1; i < len(runes); i++ {
+		lowerToUpper := unicode.IsLower(runes[i-1]) && unicode.IsUpper(runes[i])
+		acronymEnd := i+1 < len(runes) && unicode.IsUpper(runes[i-1]) && unicode.IsUpper(runes[i]) && unicode.IsLower(runes[i+1])
+		if lowerToUpper || acronymEnd {
+			parts = append(parts, string(runes[start:i]))
+			start = i
+		}
+	}
+	return append(parts, string(runes[start:]))
+}
+
+func uniqueTerms(tokens []string) []string {
+	seen := make(map[string]bool, len(tokens))
+	var terms []string
+	for _, token := range tokens {
+		if !seen[token] {
+			seen[token] = true
+			terms = append(terms, token)
+		}
+	}
+	return terms
+}
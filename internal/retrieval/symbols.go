@@ -0,0 +1,102 @@
+package retrieval
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	backticked = regexp.MustCompile("`([^`\n]+)`")
+	// pathLike matches file paths such as internal/ai/client.go or commit.go
+	pathLike = regexp.MustCompile(`\b[\w-]{2,}(?:/[\w.-]+)*\.[A-Za-z]{1,5}\b|\b[\w.-]+(?:/[\w.-]+)+\b`)
+	// identifierLike matches camelCase, PascalCase and snake_case names
+	identifierLike = regexp.MustCompile(`\b(?:[a-z]+[A-Z]\w*|[A-Z][a-z0-9]+[A-Z]\w*|[A-Za-z]+_\w+)\b`)
+)
+
+// Symbols extracts the file paths and code identifiers mentioned in text,
+// such as backticked names, paths and camelCase or snake_case words
+func Symbols(text string) []string {
+	seen := make(map[string]bool)
+	var symbols []string
+	add := func(symbol string) {
+		symbol = strings.Trim(symbol, ".,:;()[]{}'\"")
+		if len(symbol) < 3 || seen[symbol] || strings.Contains(symbol, "://") {
+			return
+		}
+		seen[symbol] = true
+		symbols = append(symbols, symbol)
+	}
+
+	for _, m := range backticked.FindAllStringSubmatch(text, -1) {
+		// Keep the name of calls and commands, e.g. `Ask(ctx)` or `yolo commit`
+		for _, field := range strings.Fields(m[1]) {
+			if i := strings.IndexByte(field, '('); i > 0 {
+				field = field[:i]
+			}
+			add(field)
+			// Qualified names like Client.Ask are written as Ask in code
+			if i := strings.LastIndexByte(field, '.'); i > 0 && i+1 < len(field) && field[i+1] >= 'A' && field[i+1] <= 'Z' {
+				add(field[i+1:])
+			}
+		}
+	}
+	for _, m := range pathLike.FindAllString(text, -1) {
+		add(m)
+	}
+	for _, m := range identifierLike.FindAllString(text, -1) {
+		add(m)
+	}
+
+	return symbols
+}
+
+// MatchSymbols ranks documents by how many of the symbols they contain.
+// A symbol in the path counts double, since it names the file itself.
+func MatchSymbols(docs []Document, symbols []string, limit int) []Result {
+	if len(symbols) == 0 {
+		return nil
+	}
+
+	var results []Result
+	for _, doc := range docs {
+		score := 0.0
+		path := strings.ToLower(doc.Path)
+		for _, symbol := range symbols {
+			switch {
+			case strings.Contains(path, strings.ToLower(symbol)):
+				score += 2
+			case containsWord(doc.Text, symbol):
+				score++
+			}
+		}
+		if score > 0 {
+			results = append(results, Result{Document: doc, Score: score})
+		}
+	}
+
+	sortResults(results)
+	if limit > 0 && len(results) > limit {
+		results = results[:limit]
+	}
+	return results
+}
+
+// containsWord reports whether word occurs in text outside a longer identifier
+func containsWord(text, word string) bool {
+	for offset := 0; ; {
+		i := strings.Index(text[offset:], word)
+		if i == -1 {
+			return false
+		}
+		start := offset + i
+		end := start + len(word)
+		if (start == 0 || !isWordByte(text[start-1])) && (end == len(text) || !isWordByte(text[end])) {
+			return true
+		}
+		offset = start + 1
+	}
+}
+
+func isWordByte(b byte) bool {
+	return b == '_' || (b >= '0' && b <= '9') || (b >= 'a' && b <= 'z') || (b >= 'A' && b <= 'Z')
+}
@@ -0,0 +1,148 @@
+package review
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// sarifSchema is the SARIF version the report follows
+const sarifSchema = "https://json.schemastore.org/sarif-2.1.0.json"
+
+// Markdown renders the findings as a report grouped by file
+func Markdown(title string, findings []Finding) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "# %s\n\n", title)
+
+	counts := Counts(findings)
+	fmt.Fprintf(&sb, "%d findings: %d errors, %d warnings, %d info\n",
+		len(findings), counts[SeverityError], counts[SeverityWarning], counts[SeverityInfo])
+	if tasks := allTasks(findings); len(tasks) > 0 {
+		fmt.Fprintf(&sb, "\nRelated tasks: %s\n", strings.Join(tasks, ", "))
+	}
+
+	for _, group := range GroupByFile(findings) {
+		fmt.Fprintf(&sb, "\n## %s\n", group[0].File)
+		for _, f := range group {
+			fmt.Fprintf(&sb, "\n- **%s** line %d, %s: %s\n", f.Severity, f.Line, f.Category, f.Message)
+			if f.Suggestion != "" {
+				fmt.Fprintf(&sb, "  - Suggestion: %s\n", strings.ReplaceAll(f.Suggestion, "\n", "\n    "))
+			}
+			if len(f.Tasks) > 0 {
+				fmt.Fprintf(&sb, "  - Tasks: %s\n", strings.Join(f.Tasks, ", "))
+			}
+		}
+	}
+
+	return sb.String()
+}
+
+// SARIF renders the findings as a SARIF 2.1.0 log, e.g. for code scanning tools
+func SARIF(toolVersion string, findings []Finding) ([]byte, error) {
+	type message struct {
+		Text string `json:"text"`
+	}
+	type rule struct {
+		ID               string  `json:"id"`
+		ShortDescription message `json:"shortDescription"`
+	}
+	type location struct {
+		PhysicalLocation struct {
+			ArtifactLocation struct {
+				URI string `json:"uri"`
+			} `json:"artifactLocation"`
+			Region struct {
+				StartLine int `json:"startLine"`
+			} `json:"region"`
+		} `json:"physicalLocation"`
+	}
+	type result struct {
+		RuleID     string                 `json:"ruleId"`
+		Level      string                 `json:"level"`
+		Message    message                `json:"message"`
+		Locations  []location             `json:"locations"`
+		Properties map[string]interface{} `json:"properties,omitempty"`
+	}
+
+	var rules []rule
+	seen := make(map[string]bool)
+	results := make([]result, 0, len(findings))
+	for _, f := range findings {
+		if !seen[f.Category] {
+			seen[f.Category] = true
+			rules = append(rules, rule{ID: f.Category, ShortDescription: message{Text: f.Category}})
+		}
+
+		text := f.Message
+		if f.Suggestion != "" {
+			text += "\n\nSuggestion: " + f.Suggestion
+		}
+		var loc location
+		loc.PhysicalLocation.ArtifactLocation.URI = f.File
+		loc.PhysicalLocation.Region.StartLine = max(f.Line, 1)
+
+		r := result{
+			RuleID:    f.Category,
+			Level:     sarifLevel(f.Severity),
+			Message:   message{Text: text},
+			Locations: []location{loc},
+		}
+		if len(f.Tasks) > 0 {
+			r.Properties = map[string]interface{}{"tasks": f.Tasks}
+		}
+		results = append(results, r)
+	}
+	sort.Slice(rules, func(i, j int) bool { return rules[i].ID < rules[j].ID })
+
+	log := map[string]interface{}{
+		"$schema": sarifSchema,
+		"version": "2.1.0",
+		"runs": []interface{}{
+			map[string]interface{}{
+				"tool": map[string]interface{}{
+					"driver": map[string]interface{}{
+						"name":           "yolo review",
+						"version":        toolVersion,
+						"informationUri": "https://github.com/baudevs/yolo.baudevs.com",
+						"rules":          rules,
+					},
+				},
+				"results": results,
+			},
+		},
+	}
+
+	return json.MarshalIndent(log, "", "  ")
+}
+
+// GroupByFile splits findings ordered by file into one slice per file
+func GroupByFile(findings []Finding) [][]Finding {
+	var groups [][]Finding
+	for i, f := range findings {
+		if i == 0 || f.File != findings[i-1].File {
+			groups = append(groups, nil)
+		}
+		groups[len(groups)-1] = append(groups[len(groups)-1], f)
+	}
+	return groups
+}
+
+// sarifLevel maps a severity to a SARIF result level
+func sarifLevel(severity string) string {
+	switch severity {
+	case SeverityError:
+		return "error"
+	case SeverityWarning:
+		return "warning"
+	}
+	return "note"
+}
+
+func allTasks(findings []Finding) []string {
+	var texts []string
+	for _, f := range findings {
+		texts = append(texts, f.Tasks...)
+	}
+	return TaskRefs(texts...)
+}
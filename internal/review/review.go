@@ -0,0 +1,231 @@
+package review
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/baudevs/yolo.baudevs.com/internal/ai"
+	"github.com/baudevs/yolo.baudevs.com/internal/git"
+	"github.com/sashabaranov/go-openai"
+)
+
+// maxBatchSize bounds the diff characters sent in one review request
+const maxBatchSize = 12000
+
+// Severities, from the most to the least serious
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+	SeverityInfo    = "info"
+)
+
+// Finding is one problem the review found in a file
+type Finding struct {
+	File       string   `json:"file"`
+	Line       int      `json:"line"`
+	Severity   string   `json:"severity"`
+	Category   string   `json:"category"`
+	Message    string   `json:"message"`
+	Suggestion string   `json:"suggestion,omitempty"`
+	Tasks      []string `json:"tasks,omitempty"` // Work items the changed code belongs to
+}
+
+// findingDraft is a finding as the AI reports it
+type findingDraft struct {
+	File       string `json:"file" description:"Path of the file, exactly as in the diff header"`
+	Line       int    `json:"line" description:"Line number in the new version of the file, from the left column"`
+	Severity   string `json:"severity" enum:"error,warning,info" description:"error: bug or vulnerability, warning: likely problem, info: improvement"`
+	Category   string `json:"category" enum:"bug,security,performance,error-handling,maintainability,style,tests,docs" description:"Kind of problem"`
+	Message    string `json:"message" description:"What is wrong and why it matters"`
+	Suggestion string `json:"suggestion,omitempty" description:"Concrete fix, code if short"`
+}
+
+// reviewResult is the structured answer to one review request
+type reviewResult struct {
+	Findings []findingDraft `json:"findings" description:"Problems found in the changed lines; empty when the change looks fine"`
+}
+
+// Review asks the AI to review the file diffs, a few files per request,
+// and returns the findings ordered by file and line
+func Review(ctx context.Context, client *ai.Client, files []git.FileDiff) ([]Finding, error) {
+	var findings []Finding
+	for _, batch := range batches(files) {
+		var sb strings.Builder
+		known := make(map[string]git.FileDiff, len(batch))
+		for _, f := range batch {
+			sb.WriteString(annotate(f))
+			sb.WriteString("\n")
+			known[f.Path] = f
+		}
+
+		prompt, err := ai.RenderPrompt("review", ai.PromptData{"Diff": sb.String()})
+		if err != nil {
+			return nil, err
+		}
+
+		result, err := ai.GenerateStructured[reviewResult](ctx, client.Provider(), ai.StructuredRequest{
+			Model:       client.Model(),
+			Messages:    []openai.ChatCompletionMessage{{Role: openai.ChatMessageRoleUser, Content: prompt}},
+			Name:        "report_findings",
+			Description: "Report the problems found in the changes",
+			Temperature: 0.2,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to review %s: %w", batchNames(batch), err)
+		}
+
+		for _, d := range result.Findings {
+			f, ok := known[d.File]
+			if !ok {
+				continue // The model invented a file
+			}
+			findings = append(findings, Finding{
+				File:       f.Path,
+				Line:       nearestChangedLine(f, d.Line),
+				Severity:   d.Severity,
+				Category:   d.Category,
+				Message:    strings.TrimSpace(d.Message),
+				Suggestion: strings.TrimSpace(d.Suggestion),
+			})
+		}
+	}
+
+	sort.SliceStable(findings, func(i, j int) bool {
+		if findings[i].File != findings[j].File {
+			return findings[i].File < findings[j].File
+		}
+		return findings[i].Line < findings[j].Line
+	})
+	return findings, nil
+}
+
+// Reviewable drops the files that cannot be reviewed: binary, deleted or without hunks
+func Reviewable(files []git.FileDiff) []git.FileDiff {
+	var reviewable []git.FileDiff
+	for _, f := range files {
+		if !f.Binary && !f.Deleted && len(f.Hunks) > 0 {
+			reviewable = append(reviewable, f)
+		}
+	}
+	return reviewable
+}
+
+// batches groups files into requests of at most maxBatchSize characters.
+// A file larger than that is split between its hunks.
+func batches(files []git.FileDiff) [][]git.FileDiff {
+	var result [][]git.FileDiff
+	var current []git.FileDiff
+	size := 0
+
+	add := func(f git.FileDiff, n int) {
+		if size > 0 && size+n > maxBatchSize {
+			result = append(result, current)
+			current, size = nil, 0
+		}
+		current = append(current, f)
+		size += n
+	}
+
+	for _, f := range files {
+		n := len(annotate(f))
+		if n <= maxBatchSize {
+			add(f, n)
+			continue
+		}
+		part := f
+		part.Hunks = nil
+		partSize := 0
+		for _, h := range f.Hunks {
+			hs := len(h.Annotated())
+			if len(part.Hunks) > 0 && partSize+hs > maxBatchSize {
+				add(part, partSize)
+				part.Hunks, partSize = nil, 0
+			}
+			part.Hunks = append(part.Hunks, h)
+			partSize += hs
+		}
+		if len(part.Hunks) > 0 {
+			add(part, partSize)
+		}
+	}
+	if len(current) > 0 {
+		result = append(result, current)
+	}
+	return result
+}
+
+// annotate renders a file diff with new line numbers for the AI
+func annotate(f git.FileDiff) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "=== %s ===\n", f.Path)
+	for _, h := range f.Hunks {
+		sb.WriteString(h.Annotated())
+	}
+	return sb.String()
+}
+
+// nearestChangedLine keeps a reported line inside the diff, moving it to the
+// closest edge of the nearest hunk when the model points outside all of them
+func nearestChangedLine(f git.FileDiff, line int) int {
+	best, bestDistance := line, -1
+	for _, h := range f.Hunks {
+		if h.Contains(line) {
+			return line
+		}
+		nearest, distance := h.NewStart, h.NewStart-line
+		if distance < 0 {
+			nearest = h.NewStart + h.NewLines - 1
+			distance = line - nearest
+		}
+		if bestDistance == -1 || distance < bestDistance {
+			best, bestDistance = nearest, distance
+		}
+	}
+	return best
+}
+
+func batchNames(batch []git.FileDiff) string {
+	names := make([]string, len(batch))
+	for i, f := range batch {
+		names[i] = f.Path
+	}
+	return strings.Join(names, ", ")
+}
+
+// taskRef matches work item IDs such as T003 or E012
+var taskRef = regexp.MustCompile(`\b[ETF]\d{3,}\b`)
+
+// TaskRefs returns the work item IDs mentioned in the texts, in order of appearance
+func TaskRefs(texts ...string) []string {
+	seen := make(map[string]bool)
+	var refs []string
+	for _, text := range texts {
+		for _, ref := range taskRef.FindAllString(strings.ToUpper(text), -1) {
+			if !seen[ref] {
+				seen[ref] = true
+				refs = append(refs, ref)
+			}
+		}
+	}
+	return refs
+}
+
+// LinkTasks attaches work item IDs to the findings: the IDs referenced for
+// their file, e.g. by the commits touching it, and the IDs shared by all files
+func LinkTasks(findings []Finding, shared []string, byFile map[string][]string) {
+	for i := range findings {
+		findings[i].Tasks = TaskRefs(strings.Join(shared, " "), strings.Join(byFile[findings[i].File], " "))
+	}
+}
+
+// Counts returns the number of findings per severity
+func Counts(findings []Finding) map[string]int {
+	counts := make(map[string]int)
+	for _, f := range findings {
+		counts[f.Severity]++
+	}
+	return counts
+}
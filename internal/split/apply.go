@@ -0,0 +1,83 @@
+package split
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/baudevs/yolo.baudevs.com/internal/git"
+)
+
+// Backup is the state before the split, enough to undo it by hand
+type Backup struct {
+	Head string // "" on a branch without commits
+	Tree string // The staged index
+}
+
+// Restore puts HEAD and the index back as they were before the split
+func (b Backup) Restore(g *git.GitOps) error {
+	if err := g.ResetSoft(b.Head); err != nil {
+		return err
+	}
+	return g.ReadTree(b.Tree)
+}
+
+// Instructions tells how to restore the backup by hand
+func (b Backup) Instructions() string {
+	reset := "git update-ref -d HEAD"
+	if b.Head != "" {
+		reset = "git reset --soft " + b.Head
+	}
+	return fmt.Sprintf("%s && git read-tree %s", reset, b.Tree)
+}
+
+// Apply commits the groups in order, staging each one from a clean index.
+// The working tree is never touched. If a step fails, the commits already
+// made are undone and the index is restored, so nothing staged is lost.
+func Apply(g *git.GitOps, groups []Group, committed func(i int, hash string)) (Backup, error) {
+	var backup Backup
+	var err error
+	if backup.Head, err = g.Head(); err != nil {
+		return backup, err
+	}
+	if backup.Tree, err = g.WriteTree(); err != nil {
+		return backup, err
+	}
+
+	fail := func(err error) (Backup, error) {
+		if restoreErr := backup.Restore(g); restoreErr != nil {
+			return backup, fmt.Errorf("%w; restoring the staged changes also failed (%v), run: %s", err, restoreErr, backup.Instructions())
+		}
+		return backup, err
+	}
+
+	if err := g.ReadTree(backup.Head); err != nil {
+		return fail(err)
+	}
+
+	for i, group := range groups {
+		if err := g.ApplyCached(Patch(group.Units)); err != nil {
+			return fail(fmt.Errorf("failed to stage commit %d: %w", i+1, err))
+		}
+		if err := g.Commit(group.Message); err != nil {
+			return fail(fmt.Errorf("failed to create commit %d: %w", i+1, err))
+		}
+		if committed != nil {
+			hash, _ := g.Head()
+			committed(i, shortHash(hash))
+		}
+	}
+
+	// Anything that was staged but not committed stays staged
+	if err := g.ReadTree(backup.Tree); err != nil {
+		return backup, fmt.Errorf("%w; run: git read-tree %s", err, backup.Tree)
+	}
+	return backup, nil
+}
+
+func shortHash(hash string) string {
+	hash = strings.TrimSpace(hash)
+	if len(hash) > 7 {
+		return hash[:7]
+	}
+	return hash
+}
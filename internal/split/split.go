@@ -0,0 +1,238 @@
+package split
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/baudevs/yolo.baudevs.com/internal/ai"
+	"github.com/baudevs/yolo.baudevs.com/internal/git"
+	"github.com/sashabaranov/go-openai"
+)
+
+// maxPromptSize bounds the diff characters shown to the AI; hunks beyond it
+// are listed with their line counts only
+const maxPromptSize = 24000
+
+// maxHunkLines bounds the lines shown of a single hunk
+const maxHunkLines = 80
+
+// Unit is the smallest piece of a change that can go into a commit: one hunk
+// of a modified file, or a whole file when it cannot be split (new, deleted,
+// binary or with a mode change)
+type Unit struct {
+	ID   string
+	File *git.FileDiff
+	Hunk int  // Index in File.Hunks, or -1 for the whole file
+	Hide bool // Only the path and line counts are sent to the AI
+}
+
+// Group is a set of units committed together
+type Group struct {
+	Message   string
+	Units     []Unit
+	Formatted bool // Message is already in the project's commit format
+}
+
+// groupDraft is a group as the AI proposes it
+type groupDraft struct {
+	Message string   `json:"message" description:"Conventional commit message for the group: subject line, then optionally a blank line and a short body"`
+	Hunks   []string `json:"hunks" description:"IDs of the hunks in the group, e.g. H1"`
+}
+
+// splitResult is the structured answer to the split request
+type splitResult struct {
+	Groups []groupDraft `json:"groups" description:"Groups of hunks, each one an atomic commit, in the order they should be committed"`
+}
+
+// Units splits file diffs into units, numbered H1, H2, ... in diff order
+func Units(files []git.FileDiff) []Unit {
+	var units []Unit
+	add := func(f *git.FileDiff, hunk int) {
+		units = append(units, Unit{ID: fmt.Sprintf("H%d", len(units)+1), File: f, Hunk: hunk})
+	}
+
+	for i := range files {
+		f := &files[i]
+		if !splittable(*f) {
+			add(f, -1)
+			continue
+		}
+		for h := range f.Hunks {
+			add(f, h)
+		}
+	}
+	return units
+}
+
+// splittable reports whether the hunks of a file can be committed separately
+func splittable(f git.FileDiff) bool {
+	if f.Binary || f.Deleted || len(f.Hunks) < 2 {
+		return false
+	}
+	return !strings.Contains(f.Header, "\nnew file mode") && !strings.Contains(f.Header, "\nold mode")
+}
+
+// Stats counts the lines the unit adds and deletes
+func (u Unit) Stats() (added, deleted int) {
+	if u.Hunk < 0 {
+		return u.File.Stats()
+	}
+	return git.FileDiff{Hunks: []git.Hunk{u.File.Hunks[u.Hunk]}}.Stats()
+}
+
+// String describes the unit, e.g. "internal/git/diff.go @@ -10,6 +10,8 @@"
+func (u Unit) String() string {
+	switch {
+	case u.Hunk >= 0:
+		return u.File.Path + " " + hunkRange(u.File.Hunks[u.Hunk].Header)
+	case u.File.Binary:
+		return u.File.Path + " (binary)"
+	case u.File.Deleted:
+		return u.File.Path + " (deleted)"
+	}
+	return u.File.Path
+}
+
+// Propose asks the AI to group the units into atomic commits. Every unit ends
+// up in exactly one group: units the AI left out get a group of their own,
+// with a message written by commitAI.
+func Propose(ctx context.Context, client *ai.Client, commitAI *ai.CommitAI, units []Unit) ([]Group, error) {
+	prompt, err := ai.RenderPrompt("commit.split", ai.PromptData{"Hunks": describe(units)})
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := ai.GenerateStructured[splitResult](ctx, client.Provider(), ai.StructuredRequest{
+		Model:       client.Model(),
+		Messages:    []openai.ChatCompletionMessage{{Role: openai.ChatMessageRoleUser, Content: prompt}},
+		Name:        "propose_commits",
+		Description: "Propose atomic commits for the staged changes",
+		Temperature: 0.2,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to propose commits: %w", err)
+	}
+
+	byID := make(map[string]Unit, len(units))
+	for _, u := range units {
+		byID[u.ID] = u
+	}
+	assigned := make(map[string]bool, len(units))
+
+	var groups []Group
+	for _, d := range result.Groups {
+		group := Group{Message: strings.TrimSpace(d.Message)}
+		for _, id := range d.Hunks {
+			id = strings.ToUpper(strings.TrimSpace(id))
+			if u, ok := byID[id]; ok && !assigned[id] {
+				group.Units = append(group.Units, u)
+				assigned[id] = true
+			}
+		}
+		if len(group.Units) > 0 && group.Message != "" {
+			groups = append(groups, group)
+		} else {
+			for _, u := range group.Units {
+				delete(assigned, u.ID)
+			}
+		}
+	}
+
+	var leftover []Unit
+	for _, u := range units {
+		if !assigned[u.ID] {
+			leftover = append(leftover, u)
+		}
+	}
+	if len(leftover) > 0 {
+		redactor, err := ai.DefaultRedactor()
+		if err != nil {
+			return nil, err
+		}
+		diff, _ := redactor.RedactDiff(Patch(leftover))
+		message, _, err := commitAI.GenerateCommitMessage(ctx, diff)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate a message for the remaining changes: %w", err)
+		}
+		groups = append(groups, Group{Message: strings.TrimSpace(message), Units: leftover, Formatted: true})
+	}
+
+	for i := range groups {
+		sortUnits(groups[i].Units)
+	}
+	return groups, nil
+}
+
+// Patch builds a patch applying only the given units, in diff order
+func Patch(units []Unit) string {
+	units = append([]Unit(nil), units...)
+	sortUnits(units)
+
+	var sb strings.Builder
+	var current *git.FileDiff
+	for _, u := range units {
+		if u.Hunk < 0 {
+			sb.WriteString(u.File.String())
+			current = nil
+			continue
+		}
+		if u.File != current {
+			sb.WriteString(u.File.Header)
+			current = u.File
+		}
+		sb.WriteString(u.File.Hunks[u.Hunk].String())
+	}
+	return sb.String()
+}
+
+// sortUnits puts units back in the order they appear in the diff
+func sortUnits(units []Unit) {
+	sort.SliceStable(units, func(i, j int) bool {
+		return unitNumber(units[i]) < unitNumber(units[j])
+	})
+}
+
+func unitNumber(u Unit) int {
+	var n int
+	fmt.Sscanf(u.ID, "H%d", &n)
+	return n
+}
+
+// describe renders the units for the prompt, each under its ID
+func describe(units []Unit) string {
+	var sb strings.Builder
+	for _, u := range units {
+		added, deleted := u.Stats()
+		fmt.Fprintf(&sb, "### %s %s (+%d -%d)\n", u.ID, u.String(), added, deleted)
+
+		if sb.Len() > maxPromptSize || u.File.Binary || u.Hide {
+			sb.WriteString("\n")
+			continue
+		}
+
+		var lines []string
+		if u.Hunk >= 0 {
+			lines = u.File.Hunks[u.Hunk].Lines
+		} else {
+			for _, h := range u.File.Hunks {
+				lines = append(lines, h.Lines...)
+			}
+		}
+		if len(lines) > maxHunkLines {
+			lines = append(lines[:maxHunkLines:maxHunkLines], fmt.Sprintf("... %d more lines", len(lines)-maxHunkLines))
+		}
+		sb.WriteString(strings.Join(lines, "\n"))
+		sb.WriteString("\n\n")
+	}
+	return sb.String()
+}
+
+// hunkRange returns the "@@ -a,b +c,d @@" part of a hunk header
+func hunkRange(header string) string {
+	if i := strings.Index(header[2:], "@@"); i >= 0 {
+		return header[:i+4]
+	}
+	return header
+}
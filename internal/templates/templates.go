@@ -74,73 +74,27 @@ Use these emojis consistently:
 
 Remember: The goal is to maintain a clear, traceable history of the project's evolution.`
 
-// PromptsTemplate provides default AI prompts configuration
+// PromptsTemplate provides the project-level AI prompt overrides
 var PromptsTemplate = `# YOLO AI Prompts
+#
+# Project overrides for the prompts YOLO sends to the AI.
+# Prompts are Go text/template strings; an entry here replaces the
+# global or built-in prompt with the same name.
+#
+# List prompt names:      yolo prompt list
+# See the prompt in use:  yolo prompt show <name> --effective
 
 version: 1.0.0
 date: ${date}
 
 prompts:
-  - name: create-epic
-    description: Create a new epic
-    template: |
-      Create a new epic following YOLO methodology:
-      - Title: ${title}
-      - Description: ${description}
-      - Success Metrics
-      - Timeline
-      - Related Features
-      - Status Indicators
-
-  - name: add-feature
-    description: Add a feature to an epic
-    template: |
-      Add a feature to epic ${epic}:
-      - Feature Name: ${name}
-      - Description
-      - Implementation Tasks
-      - Dependencies
-      - Status Updates
-
-  - name: create-task
-    description: Create implementation task
-    template: |
-      Create task for feature ${feature}:
-      - Task Name: ${name}
-      - Implementation Details
-      - Acceptance Criteria
-      - Dependencies
-      - Progress Tracking
-
-  - name: update-status
-    description: Update component status
-    template: |
-      Update status for ${component}:
-      - Current Status
-      - Progress Details
-      - Blockers/Issues
-      - Next Steps
-      - Related Updates
-
-  - name: document-decision
-    description: Record architectural decision
-    template: |
-      Document decision for ${topic}:
-      - Context
-      - Considered Options
-      - Decision
-      - Consequences
-      - Related Components
-
-  - name: conventional-commit
-    description: Generate commit message
-    template: |
-      Create conventional commit for ${changes}:
-      - Type (feat/fix/etc)
-      - Scope
-      - Description
-      - Breaking Changes
-      - Related Issues`
+  # - name: epic.description
+  #   template: |-
+  #     Create a comprehensive epic description for:
+  #     "{{.Description}}"
+  #
+  #     Include goals, business value and success metrics.
+`
 
 // HistoryTemplate provides the initial HISTORY.yml structure
 var HistoryTemplate = `version: 1.0.0
@@ -0,0 +1,73 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// ForEach calls fn for every index from 0 to n-1, running at most limit calls
+// at a time. After the first failure the context passed to fn is cancelled and
+// no new calls start. It returns the error of every index: nil when fn
+// succeeded, and the context's error for indexes that never started.
+func ForEach(ctx context.Context, n, limit int, fn func(ctx context.Context, i int) error) []error {
+	if limit < 1 {
+		limit = 1
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	errs := make([]error, n)
+	sem := make(chan struct{}, limit)
+	var wg sync.WaitGroup
+
+	for i := 0; i < n; i++ {
+		acquired := false
+		select {
+		case sem <- struct{}{}:
+			acquired = true
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			if acquired {
+				<-sem
+			}
+			for j := i; j < n; j++ {
+				errs[j] = ctx.Err()
+			}
+			break
+		}
+
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			if err := fn(ctx, i); err != nil {
+				errs[i] = err
+				cancel()
+			}
+		}(i)
+	}
+
+	wg.Wait()
+	return errs
+}
+
+// FirstError returns the first error that is not a consequence of
+// cancellation, or else the first error at all
+func FirstError(errs []error) error {
+	var first error
+	for _, err := range errs {
+		if err == nil {
+			continue
+		}
+		if !errors.Is(err, context.Canceled) {
+			return err
+		}
+		if first == nil {
+			first = err
+		}
+	}
+	return first
+}